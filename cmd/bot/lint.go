@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/pkg/logger"
+)
+
+// runLintCodeowners lints a local CODEOWNERS file and returns the process exit code.
+// Owner membership cannot be verified offline, so only syntax, unreachable patterns
+// and approval counts of explicitly listed users are checked.
+func runLintCodeowners(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: bot lint-codeowners <file>")
+		return 2
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open %s: %v\n", args[0], err)
+		return 2
+	}
+	defer file.Close()
+
+	parser := codeowners.NewCodeownersParser(logger.NewWithLevel("ERROR"))
+	parsed, err := parser.Parse(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse %s: %v\n", args[0], err)
+		return 2
+	}
+
+	issues := codeowners.Lint(parsed, nil)
+	for _, issue := range issues {
+		location := args[0]
+		if issue.LineNumber > 0 {
+			location = fmt.Sprintf("%s:%d", args[0], issue.LineNumber)
+		}
+		fmt.Printf("%s: [%s] %s\n", location, issue.Kind, issue.Message)
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("%d issue(s) found\n", len(issues))
+		return 1
+	}
+
+	fmt.Println("No issues found")
+	return 0
+}
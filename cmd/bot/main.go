@@ -19,6 +19,14 @@ import (
 )
 
 func main() {
+	// Offline subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint-codeowners":
+			os.Exit(runLintCodeowners(os.Args[2:]))
+		}
+	}
+
 	// Initialize logger
 	log := logger.New()
 
@@ -55,11 +63,19 @@ func main() {
 
 	log.Info("Connected to GitLab server", "server", cfg.GitLab.BaseURL)
 
-	// Initialize storage
-	store := storage.NewMemoryStorage()
+	// Initialize storage, shared through Redis by the replicas processing the queue
+	var store storage.Storage = storage.NewMemoryStorage()
+	if cfg.Queue.Enabled {
+		redisStore := storage.NewRedisStorage(cfg.Queue.Redis.Host, cfg.Queue.Redis.Password, cfg.Queue.Redis.DB, "gitlab:mr:result")
+		defer redisStore.Close()
+		store = redisStore
+	}
 
 	// Initialize conformity checker
-	checker := conformity.NewChecker(cfg.Rules, gitlabClient, log)
+	checker, err := conformity.NewChecker(cfg.Rules, cfg.Report, gitlabClient, log)
+	if err != nil {
+		log.Fatal("Failed to create conformity checker", "error", err)
+	}
 
 	// Initialize HTTP server
 	srv := server.NewServer(cfg, gitlabClient, checker, store, log, queueManager)
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"gitlab-mr-conformity-bot/internal/gitlab"
 	"gitlab-mr-conformity-bot/pkg/logger"
+	"slices"
 	"strings"
 	"time"
 
+	doublestar "github.com/bmatcuk/doublestar/v4"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
@@ -28,6 +31,15 @@ type Config struct {
 	Rules RulesConfig `mapstructure:"rules"`
 
 	Queue QueueConfig `mapstructure:"queue"`
+
+	Report ReportServerConfig `mapstructure:"report"`
+}
+
+// ReportServerConfig holds the report templates and message catalogues of the server, overriding
+// the built-in ones
+type ReportServerConfig struct {
+	TemplatesDir string `mapstructure:"templates_dir"` // *.tmpl files redefining report templates
+	LocalesDir   string `mapstructure:"locales_dir"`   // <language>.yaml message catalogues
 }
 
 // QueueConfig holds Redis queue configuration
@@ -52,12 +64,67 @@ type QueueSettings struct {
 }
 
 type RulesConfig struct {
-	Title       TitleConfig       `mapstructure:"title"`
-	Description DescriptionConfig `mapstructure:"description"`
-	Branch      BranchConfig      `mapstructure:"branch"`
-	Commits     CommitsConfig     `mapstructure:"commits"`
-	Approvals   ApprovalsConfig   `mapstructure:"approvals"`
-	Squash      SquashConfig      `mapstructure:"squash"`
+	Title          TitleConfig          `mapstructure:"title"`
+	Description    DescriptionConfig    `mapstructure:"description"`
+	Branch         BranchConfig         `mapstructure:"branch"`
+	Commits        CommitsConfig        `mapstructure:"commits"`
+	Approvals      ApprovalsConfig      `mapstructure:"approvals"`
+	Squash         SquashConfig         `mapstructure:"squash"`
+	CodeownersLint CodeownersLintConfig `mapstructure:"codeowners_lint"`
+	Paths          PathsConfig          `mapstructure:"paths"`
+	Size           SizeConfig           `mapstructure:"size"`
+	Pipeline       PipelineConfig       `mapstructure:"pipeline"`
+	Signature      SignatureConfig      `mapstructure:"signature"`
+	Author         AuthorConfig         `mapstructure:"author"`
+	Consistency    ConsistencyConfig    `mapstructure:"consistency"`
+	Custom         []CustomRuleConfig   `mapstructure:"custom"`
+	External       []ExternalRuleConfig `mapstructure:"external"`
+	Instances      []RuleInstance       `mapstructure:"instances"`
+	Order          []string             `mapstructure:"order"` // Rule names or kinds evaluated first
+	Draft          DraftConfig          `mapstructure:"draft"`
+	Exemptions     []Exemption          `mapstructure:"exemptions"`
+	Profiles       []RuleProfile        `mapstructure:"profiles"`
+	Report         ReportConfig         `mapstructure:"report"`
+}
+
+// ReportConfig selects the language of the report and customises its templates and messages
+type ReportConfig struct {
+	Language string                 `mapstructure:"language"` // Catalogue language, defaults to en
+	Template string                 `mapstructure:"template"` // Template definitions overriding named report templates
+	Messages map[string]interface{} `mapstructure:"messages"` // Messages overriding the catalogue, nested like catalogues
+}
+
+// DraftConfig selects how draft merge requests are checked
+type DraftConfig struct {
+	Mode  string   `mapstructure:"mode"`  // full (default), skip, silent or reduced
+	Rules []string `mapstructure:"rules"` // Rule names or kinds checked in reduced mode
+}
+
+// RuleInstance enables a registered rule by kind, such as `size` or `paths`. Its settings
+// override those of the rule section, so several instances of a rule can coexist.
+type RuleInstance struct {
+	Kind     string                 `mapstructure:"kind"`
+	Name     string                 `mapstructure:"name"` // Defaults to the rule name, must be unique
+	Settings map[string]interface{} `mapstructure:"settings"`
+}
+
+// Exemption waives rules for merge requests matching all its criteria, such as those of
+// dependency bots. An exemption without criteria matches nothing.
+type Exemption struct {
+	Name           string   `mapstructure:"name"`
+	Authors        []string `mapstructure:"authors"`         // Author usernames
+	Bot            bool     `mapstructure:"bot"`             // Authors flagged as bots by GitLab
+	SourceBranches []string `mapstructure:"source_branches"` // Globs
+	Labels         []string `mapstructure:"labels"`          // Any of the labels
+	Rules          []string `mapstructure:"rules"`           // Rule names or kinds waived, all when empty
+}
+
+// RuleProfile overrides rule settings for merge requests whose branches match its globs
+type RuleProfile struct {
+	Name           string                 `mapstructure:"name"`
+	TargetBranches []string               `mapstructure:"target_branches"`
+	SourceBranches []string               `mapstructure:"source_branches"`
+	Rules          map[string]interface{} `mapstructure:"rules"`
 }
 
 type TitleConfig struct {
@@ -67,6 +134,7 @@ type TitleConfig struct {
 	Conventional   ConventionalConfig `mapstructure:"conventional"`
 	ForbiddenWords []string           `mapstructure:"forbidden_words"`
 	Jira           JiraConfig         `mapstructure:"jira"`
+	AutoFix        bool               `mapstructure:"auto_fix"` // Apply the proposed title without waiting for a command
 }
 
 type DescriptionConfig struct {
@@ -80,6 +148,15 @@ type BranchConfig struct {
 	Enabled         bool     `mapstructure:"enabled"`
 	AllowedPrefixes []string `mapstructure:"allowed_prefixes"`
 	ForbiddenNames  []string `mapstructure:"forbidden_names"`
+	Patterns        []string `mapstructure:"patterns"`
+	Templates       []string `mapstructure:"templates"`
+	MaxLength       int      `mapstructure:"max_length"`
+	Lowercase       bool     `mapstructure:"lowercase"`
+	AllowedChars    string   `mapstructure:"allowed_chars"`
+	MatchTitleJira  bool     `mapstructure:"match_title_jira"`
+	// Values of the {type} and {jira} placeholders, defaulting to the title settings
+	Types    []string `mapstructure:"types"`
+	JiraKeys []string `mapstructure:"jira_keys"`
 }
 
 type CommitsConfig struct {
@@ -87,23 +164,145 @@ type CommitsConfig struct {
 	MaxLength    int                `mapstructure:"max_length"`
 	Conventional ConventionalConfig `mapstructure:"conventional"`
 	Jira         JiraConfig         `mapstructure:"jira"`
+	// Policies for special commits: "check", "skip" or "fail"
+	MergeCommits  string   `mapstructure:"merge_commits"`
+	RevertCommits string   `mapstructure:"revert_commits"`
+	FixupCommits  string   `mapstructure:"fixup_commits"`
+	BotCommits    string   `mapstructure:"bot_commits"`
+	BotAuthors    []string `mapstructure:"bot_authors"`
+	// Validate the squash commit message instead of the commits when the merge request is squashed
+	SquashAware    bool   `mapstructure:"squash_aware"`
+	SquashTemplate string `mapstructure:"squash_template"`
+	// Also validate the merge commit message when the merge request is not squashed
+	MergeTemplate string `mapstructure:"merge_template"`
 }
 
 type ApprovalsConfig struct {
-	Enabled       bool `mapstructure:"enabled"`
-	MinCount      int  `mapstructure:"min_count"`
-	UseCodeowners bool `mapstructure:"use_codeowners"`
+	Enabled         bool `mapstructure:"enabled"`
+	MinCount        int  `mapstructure:"min_count"`
+	UseCodeowners   bool `mapstructure:"use_codeowners"`
+	AssignReviewers bool `mapstructure:"assign_reviewers"`
 }
 
 type SquashConfig struct {
 	Enabled          bool     `mapstructure:"enabled"`
 	EnforceBranches  []string `mapstructure:"enforce_branches"`
 	DisallowBranches []string `mapstructure:"disallow_branches"`
+	Default          string   `mapstructure:"default"`  // Policy of other branches: require (default), disallow or allow
+	AutoFix          bool     `mapstructure:"auto_fix"` // Update the squash setting of the merge request to match
+}
+
+type CodeownersLintConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type PathsConfig struct {
+	Enabled        bool               `mapstructure:"enabled"`
+	MaxFiles       int                `mapstructure:"max_files"`
+	Forbidden      []string           `mapstructure:"forbidden"`
+	RequireLabels  []PathLabelPolicy  `mapstructure:"require_labels"`
+	RequireChanges []PathChangePolicy `mapstructure:"require_changes"`
+}
+
+// PathLabelPolicy requires labels on merge requests changing matching paths
+type PathLabelPolicy struct {
+	Paths  []string `mapstructure:"paths"`
+	Labels []string `mapstructure:"labels"`
+}
+
+// PathChangePolicy requires a companion change when matching paths are modified
+type PathChangePolicy struct {
+	Paths []string `mapstructure:"paths"`
+	With  []string `mapstructure:"with"`
+}
+
+type SizeConfig struct {
+	Enabled          bool           `mapstructure:"enabled"`
+	Warning          SizeThresholds `mapstructure:"warning"`
+	Error            SizeThresholds `mapstructure:"error"`
+	Exclude          []string       `mapstructure:"exclude"`
+	ExcludeGenerated bool           `mapstructure:"exclude_generated"`
+	ExemptBranches   []string       `mapstructure:"exempt_branches"`
+}
+
+// SizeThresholds holds merge request size limits, a zero value disables the limit
+type SizeThresholds struct {
+	Additions int `mapstructure:"additions"`
+	Deletions int `mapstructure:"deletions"`
+	Lines     int `mapstructure:"lines"`
+	Files     int `mapstructure:"files"`
+}
+
+type PipelineConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	RequiredJobs   []string `mapstructure:"required_jobs"`
+	RequiredStages []string `mapstructure:"required_stages"`
+	RequireHeadSHA bool     `mapstructure:"require_head_sha"`
+}
+
+type SignatureConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	RequireVerified bool     `mapstructure:"require_verified"`
+	RequireDCO      bool     `mapstructure:"require_dco"`
+	BotAuthors      []string `mapstructure:"bot_authors"`
+}
+
+type AuthorConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	AllowedDomains []string `mapstructure:"allowed_domains"`
+	CheckCommitter bool     `mapstructure:"check_committer"`
+	RequireMember  bool     `mapstructure:"require_member"`
+	BotAuthors     []string `mapstructure:"bot_authors"`
+}
+
+type ConsistencyConfig struct {
+	Enabled                 bool `mapstructure:"enabled"`
+	TitleKeyInBranch        bool `mapstructure:"title_key_in_branch"`
+	TitleKeyInDescription   bool `mapstructure:"title_key_in_description"`
+	CommitKeysInTitle       bool `mapstructure:"commit_keys_in_title"`
+	TitleTypeMatchesCommits bool `mapstructure:"title_type_matches_commits"`
+	// Conventional types from highest to lowest impact
+	TypeOrder  []string `mapstructure:"type_order"`
+	JiraKeys   []string `mapstructure:"jira_keys"`
+	BotAuthors []string `mapstructure:"bot_authors"`
+}
+
+// CustomRuleConfig declares a rule as expressions over the merge request, see rules.CustomRule
+type CustomRuleConfig struct {
+	Name      string `mapstructure:"name"`
+	Severity  string `mapstructure:"severity"`  // error or warning
+	When      string `mapstructure:"when"`      // Optional, the rule only applies when true
+	Condition string `mapstructure:"condition"` // The rule passes when true
+	// Go templates rendered with the expression variables, e.g. {{ .title }}
+	Message    string `mapstructure:"message"`
+	Suggestion string `mapstructure:"suggestion"`
+}
+
+// externalKind is the rule kind of external rules
+const externalKind = "external"
+
+// ExternalRuleConfig declares an external rule, only allowed in the server configuration
+type ExternalRuleConfig struct {
+	Name     string            `mapstructure:"name"`
+	Severity string            `mapstructure:"severity"` // error or warning
+	URL      string            `mapstructure:"url"`
+	Headers  map[string]string `mapstructure:"headers"` // Values expand ${ENV} variables
+	Timeout  time.Duration     `mapstructure:"timeout"` // Per attempt
+	Retries  int               `mapstructure:"retries"`
+	// FailurePolicy is closed to report unreachable services as unevaluated, open to pass
+	FailurePolicy string `mapstructure:"failure_policy"`
+	IncludeFiles  bool   `mapstructure:"include_files"`
 }
 
 type ConventionalConfig struct {
 	Types  []string `mapstructure:"types"`
 	Scopes []string `mapstructure:"scopes"`
+	// Body and footer checks, only applied to commit messages
+	RequireBlankLine      bool     `mapstructure:"require_blank_line"`
+	BodyMaxLineLength     int      `mapstructure:"body_max_line_length"`
+	EnforceBreakingChange bool     `mapstructure:"enforce_breaking_change"`
+	RequiredTrailers      []string `mapstructure:"required_trailers"`
+	AllowedFooters        []string `mapstructure:"allowed_footers"`
 }
 
 type JiraConfig struct {
@@ -167,14 +366,24 @@ func NewConfigLoader(defaultConfig RulesConfig, client *gitlab.Client, log *logg
 	}
 }
 
-// LoadConfig loads configuration for a project, trying repository config first, then falling back to default
-func (cl *ConfigLoader) LoadConfig(projectID interface{}) (RulesConfig, error) {
+// ConfigSource tells where the configuration of a project comes from
+type ConfigSource string
+
+const (
+	ConfigSourceDefault    ConfigSource = "default"    // Configuration of the server
+	ConfigSourceRepository ConfigSource = "repository" // .mr-conform.yaml file of the repository
+)
+
+// LoadConfig loads configuration for a project, trying repository config first, then falling back to default.
+// The source of the configuration is returned along with it.
+func (cl *ConfigLoader) LoadConfig(projectID interface{}) (RulesConfig, ConfigSource, error) {
 	repoConfig, err := cl.loadRepositoryConfig(projectID)
 	if err != nil {
 		cl.logger.Debug("Using default configuration", "reason", err.Error())
 	}
 
-	return cl.selectConfig(repoConfig), nil
+	rulesConfig, source := cl.selectConfig(repoConfig)
+	return rulesConfig, source, nil
 }
 
 // loadRepositoryConfig attempts to load config from repository, returns nil if not found or invalid
@@ -210,17 +419,157 @@ func (cl *ConfigLoader) loadRepositoryConfig(projectID interface{}) (*RulesConfi
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// External rules send merge request data and server secrets to their URL, only the server may declare them
+	if repoConfig.Rules.keepServerExternalRules(cl.defaultConfig) {
+		cl.logger.Warn("Ignoring external rules declared by the repository, they can only be set in the server configuration", "projectId", projectID)
+	}
+
 	cl.logger.Debug("Successfully loaded config from repository")
 	return &repoConfig.Rules, nil
 }
 
 // selectConfig returns repository config if available, otherwise default config
-func (cl *ConfigLoader) selectConfig(repoConfig *RulesConfig) RulesConfig {
+func (cl *ConfigLoader) selectConfig(repoConfig *RulesConfig) (RulesConfig, ConfigSource) {
 	if repoConfig != nil {
 		cl.logger.Debug("Using repository configuration")
-		return *repoConfig
+		return *repoConfig, ConfigSourceRepository
 	}
 
 	cl.logger.Info("Using default configuration")
-	return cl.defaultConfig
+	return cl.defaultConfig, ConfigSourceDefault
+}
+
+// keepServerExternalRules replaces the external rules of a repository configuration, including external
+// instances and those set by profiles, with the ones of the server. It reports whether any were dropped.
+func (rc *RulesConfig) keepServerExternalRules(server RulesConfig) bool {
+	dropped := len(rc.External) > 0
+	rc.External = server.External
+
+	instances := make([]RuleInstance, 0, len(rc.Instances))
+	for _, instance := range rc.Instances {
+		if instance.Kind == externalKind {
+			dropped = true
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	for _, instance := range server.Instances {
+		if instance.Kind == externalKind {
+			instances = append(instances, instance)
+		}
+	}
+	rc.Instances = instances
+
+	for _, profile := range rc.Profiles {
+		if _, found := profile.Rules[externalKind]; found {
+			delete(profile.Rules, externalKind)
+			dropped = true
+		}
+		if list, ok := profile.Rules["instances"].([]interface{}); ok {
+			kept := make([]interface{}, 0, len(list))
+			for _, instance := range list {
+				if settings, ok := instance.(map[string]interface{}); ok && settings["kind"] == externalKind {
+					dropped = true
+					continue
+				}
+				kept = append(kept, instance)
+			}
+			profile.Rules["instances"] = kept
+		}
+	}
+	return dropped
+}
+
+// ForBranches returns the configuration with the profiles matching the merge request branches applied in order.
+// A profile matches when both its target and source branch globs match, an empty list matching any branch.
+func (rc RulesConfig) ForBranches(sourceBranch, targetBranch string) (RulesConfig, []string, error) {
+	effective := rc
+	effective.Profiles = nil
+
+	var applied []string
+	for i, profile := range rc.Profiles {
+		name := profile.Name
+		if name == "" {
+			name = fmt.Sprintf("profile %d", i+1)
+		}
+
+		if len(profile.TargetBranches) == 0 && len(profile.SourceBranches) == 0 {
+			return rc, nil, fmt.Errorf("%s: at least one of target_branches or source_branches is required", name)
+		}
+		targetMatch, err := matchesAnyBranch(profile.TargetBranches, targetBranch)
+		if err != nil {
+			return rc, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		sourceMatch, err := matchesAnyBranch(profile.SourceBranches, sourceBranch)
+		if err != nil {
+			return rc, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if !targetMatch || !sourceMatch {
+			continue
+		}
+
+		if err := DecodeOverrides(profile.Rules, &effective); err != nil {
+			return rc, nil, fmt.Errorf("%s: invalid rules: %w", name, err)
+		}
+		applied = append(applied, name)
+	}
+
+	return effective, applied, nil
+}
+
+// DecodeOverrides decodes settings read from YAML into an existing configuration, only replacing
+// the fields present in the settings. Zeroing replaced fields gives overridden lists their own
+// backing array, leaving the original configuration untouched.
+func DecodeOverrides(settings map[string]interface{}, result interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		WeaklyTypedInput: true,
+		ZeroFields:       true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(settings)
+}
+
+// Matches reports whether a merge request meets every criterion of the exemption
+func (e Exemption) Matches(author string, bot bool, sourceBranch string, labels []string) (bool, error) {
+	if len(e.Authors) == 0 && !e.Bot && len(e.SourceBranches) == 0 && len(e.Labels) == 0 {
+		return false, nil
+	}
+
+	if len(e.Authors) > 0 && !slices.ContainsFunc(e.Authors, func(a string) bool { return strings.EqualFold(a, author) }) {
+		return false, nil
+	}
+	if e.Bot && !bot {
+		return false, nil
+	}
+	if match, err := matchesAnyBranch(e.SourceBranches, sourceBranch); err != nil || !match {
+		return false, err
+	}
+	if len(e.Labels) > 0 && !slices.ContainsFunc(e.Labels, func(l string) bool { return slices.Contains(labels, l) }) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// matchesAnyBranch reports whether a branch matches any of the globs, an empty list matching every branch
+func matchesAnyBranch(patterns []string, branch string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	for _, pattern := range patterns {
+		match, err := doublestar.Match(pattern, branch)
+		if err != nil {
+			return false, fmt.Errorf("invalid branch pattern '%s': %v", pattern, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
 }
@@ -1,10 +1,15 @@
 package conformity
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"log"
+	"runtime/debug"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"gitlab-mr-conformity-bot/internal/config"
@@ -12,6 +17,7 @@ import (
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
 	"gitlab-mr-conformity-bot/internal/conformity/rules"
 	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
 	"gitlab-mr-conformity-bot/pkg/logger"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
@@ -21,122 +27,410 @@ type Checker struct {
 	configLoader     *config.ConfigLoader
 	ruleBuilder      *RuleBuilder
 	summaryGenerator *SummaryGenerator
+	bundle           *i18n.Bundle
 	gitlabClient     *gitlab.Client
 	logger           *logger.Logger
 }
 
+// Draft modes, selecting how draft merge requests are checked
+const (
+	DraftModeFull    = "full"    // Check and report like ready merge requests
+	DraftModeSkip    = "skip"    // Do not check
+	DraftModeSilent  = "silent"  // Check and store the result without reporting it
+	DraftModeReduced = "reduced" // Check and report the draft rules only
+)
+
 type CheckResult struct {
-	Passed   bool
-	Failures []RuleFailure
-	Summary  string
+	Passed       bool
+	Failures     []RuleFailure
+	Summary      string
+	SHA          string       // Head commit the merge request was checked at
+	Rules        []string     // Names of the evaluated rules
+	AppliedFixes []AppliedFix // Fixes applied automatically before the check
+	Silent       bool         // Draft result to store without reporting it on the merge request
+	Exemptions   []string     // Names of the exemptions matching the merge request
+	Waived       []string     // Names of the rules waived by the exemptions
+	Fingerprint  string       // Configuration and merge request content the rules were checked against
 }
 
 type RuleFailure struct {
-	RuleName   string
-	Severity   rules.Severity
-	Error      []string
-	Suggestion []string
+	RuleName    string
+	Severity    rules.Severity
+	Error       []string
+	Suggestion  []string
+	Fixes       []rules.Fix
+	Unevaluated bool // Rule could not be evaluated (missing data or internal error)
+}
+
+// AppliedFix is a fix applied to a merge request, along with the value it replaced
+type AppliedFix struct {
+	RuleName string
+	Field    rules.FixField
+	Previous string
+	Value    string
 }
 
-func NewChecker(defaultConfig config.RulesConfig, client *gitlab.Client, log *logger.Logger) *Checker {
+// NewChecker creates a checker, its reports using the templates and message catalogues of the server
+// along with the built-in ones
+func NewChecker(defaultConfig config.RulesConfig, reportConfig config.ReportServerConfig, client *gitlab.Client, log *logger.Logger) (*Checker, error) {
+	summaryGenerator, err := NewSummaryGenerator(reportConfig.TemplatesDir, log)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := i18n.NewBundle(reportConfig.LocalesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message catalogues: %w", err)
+	}
+
 	return &Checker{
 		configLoader:     config.NewConfigLoader(defaultConfig, client, log),
-		ruleBuilder:      NewRuleBuilder(),
-		summaryGenerator: NewSummaryGenerator(),
+		ruleBuilder:      NewRuleBuilder(client, log),
+		summaryGenerator: summaryGenerator,
+		bundle:           bundle,
 		gitlabClient:     client,
 		logger:           log,
-	}
+	}, nil
 }
 
+// CheckMergeRequest evaluates every enabled rule against a merge request
 func (c *Checker) CheckMergeRequest(projectID interface{}, mrID int) (*CheckResult, error) {
+	return c.RecheckMergeRequest(projectID, mrID, nil, nil)
+}
+
+// RecheckMergeRequest only re-evaluates the rules depending on the changed inputs and reuses the
+// previous results of the others. Every rule is evaluated when no inputs are given or when the
+// previous result is missing or was computed for another head commit.
+func (c *Checker) RecheckMergeRequest(projectID interface{}, mrID int, changed []rules.Input, previous *CheckResult) (*CheckResult, error) {
+	return c.check(projectID, mrID, changed, previous, true)
+}
+
+// check evaluates the rules, applying the automatic fixes of failed rules when autoFix is set
+func (c *Checker) check(projectID interface{}, mrID int, changed []rules.Input, previous *CheckResult, autoFix bool) (*CheckResult, error) {
 	// Load configuration (repository or default)
-	finalConfig, err := c.configLoader.LoadConfig(projectID)
+	finalConfig, configSource, err := c.configLoader.LoadConfig(projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Build rules based on configuration
-	rulesList := c.ruleBuilder.BuildRules(finalConfig)
-
-	// Get merge request and commits
-	mr, commits, approvals, err := c.fetchMergeRequestData(projectID, mrID)
+	// Get merge request details
+	mr, err := c.gitlabClient.GetMergeRequest(projectID, mrID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+	fingerprint := checkFingerprint(finalConfig, mr)
+
+	// Messages of the report language, with those overridden by the configuration
+	messages := c.messages(finalConfig.Report)
+
+	// Exemptions waive rules, or the whole check, for merge requests such as those of dependency bots
+	exemptions, exempted, exemptAll := c.matchExemptions(finalConfig.Exemptions, mr)
+	if exemptAll {
+		c.logger.Info("Merge request exempted from checks", "projectId", projectID, "mrId", mrID, "exemptions", exemptions)
+		result := &CheckResult{Passed: true, SHA: mr.SHA, Exemptions: exemptions}
+		report := newReport(result, nil, configSource)
+		report.Exempted = true
+		result.Summary = c.summaryGenerator.GenerateSummary(report, messages, finalConfig.Report.Template)
+		return result, nil
 	}
 
-	var co []*codeowners.PatternGroup
+	// Build rules based on configuration and the profiles matching the merge request branches
+	rulesList, waived, finalConfig := c.ruleBuilder.BuildRules(finalConfig, mr, exempted, messages)
+
+	// Drafts are checked according to the draft mode, fully once marked as ready
+	draftMode := DraftModeFull
+	if mr.Draft {
+		switch finalConfig.Draft.Mode {
+		case "", DraftModeFull:
+		case DraftModeSkip:
+			c.logger.Debug("Skipping draft merge request", "projectId", projectID, "mrId", mrID)
+			return &CheckResult{Passed: true, SHA: mr.SHA, Silent: true}, nil
+		case DraftModeSilent, DraftModeReduced:
+			draftMode = finalConfig.Draft.Mode
+		default:
+			c.logger.Warn("Unknown draft mode, checking fully", "mode", finalConfig.Draft.Mode)
+		}
+	}
 
+	var co []*codeowners.PatternGroup
+	var approvals *common.Approvals
+	var commits []*gitlabapi.Commit
 	var members []*gitlabapi.ProjectMember
+	var diffs []*common.Diff
+
+	// Errors of optional data sources, reported by the rules depending on them
+	sourceErrors := make(map[rules.DataSource]error)
+
+	// Get mr approvers, also used to assign reviewers
+	approvals, err = c.gitlabClient.ListMergeRequestApprovals(projectID, mrID)
+	if err != nil {
+		c.logger.Warn("Failed to get approvals", "error", err)
+		sourceErrors[rules.DataSourceApprovals] = err
+	}
+
+	if rules.RequiresDataSource(rulesList, rules.DataSourceCommits) {
+		// Get commits for commit-related rules
+		commits, err = c.gitlabClient.ListMergeRequestCommits(projectID, mrID)
+		if err != nil {
+			c.logger.Warn("Failed to get commits", "error", err)
+			sourceErrors[rules.DataSourceCommits] = err
+		}
+	}
 
-	if finalConfig.Approvals.UseCodeowners {
+	if rules.RequiresDataSource(rulesList, rules.DataSourceMembers) {
 		// Get project members
 		members, err = c.gitlabClient.ListProjectMembers(projectID)
 		if err != nil {
-			c.logger.Info("Failed to list project members", "error", err)
+			c.logger.Warn("Failed to list project members", "error", err)
+			sourceErrors[rules.DataSourceMembers] = err
 		}
-		// Get CODEOWNERS file from repository
-		co, err = c.getCodeowners(projectID, mrID, members)
+	}
+
+	needsCodeowners := rules.RequiresDataSource(rulesList, rules.DataSourceCodeowners)
+	if needsCodeowners || rules.RequiresDataSource(rulesList, rules.DataSourceDiffs) {
+		// Get the changes once, for the code owners and every rule depending on them
+		diffs, err = c.gitlabClient.GetAllDiffs(projectID, mrID)
 		if err != nil {
-			c.logger.Info("No CODEOWNERS file found in repository, skipping", "error", err)
+			c.logger.Warn("Failed to list changes", "error", err)
+			sourceErrors[rules.DataSourceDiffs] = err
+		}
+	}
+
+	if needsCodeowners {
+		// Get CODEOWNERS file from repository
+		if err := sourceErrors[rules.DataSourceDiffs]; err != nil {
+			sourceErrors[rules.DataSourceCodeowners] = err
+		} else if co, err = c.getCodeowners(projectID, members, common.DiffPaths(diffs)); err != nil {
+			c.logger.Warn("Failed to process CODEOWNERS", "error", err)
+			sourceErrors[rules.DataSourceCodeowners] = err
 		}
+	}
 
+	// Keep the results of rules unaffected by the changed inputs, as long as neither the
+	// configuration nor the merge request changed since the previous check
+	var reused map[string]*RuleFailure
+	if len(changed) > 0 && previous != nil && fingerprint != "" && previous.Fingerprint == fingerprint {
+		reused = make(map[string]*RuleFailure)
+		for _, rule := range rulesList {
+			if !rules.DependsOnAny(rule, changed) && slices.Contains(previous.Rules, rule.Name()) {
+				reused[rule.Name()] = previous.failure(rule.Name())
+			}
+		}
+		c.logger.Debug("Rechecking rules affected by changed inputs", "inputs", changed, "reused", len(reused))
 	}
 
 	// Execute rule checks
-	failures := c.executeRuleChecks(rulesList, mr, commits, approvals, co, members)
+	failures := c.executeRuleChecks(rulesList, mr, commits, approvals, co, members, diffs, sourceErrors, reused, messages)
+
+	// Request reviews from the code owners still needed
+	if finalConfig.Approvals.Enabled && finalConfig.Approvals.UseCodeowners && finalConfig.Approvals.AssignReviewers &&
+		len(sourceErrors) == 0 && mr.State == "opened" {
+		if err := c.assignReviewers(projectID, mr, co, approvals, members); err != nil {
+			c.logger.Warn("Failed to assign reviewers", "error", err)
+		}
+	}
+
+	// Apply the automatic fixes, then check again so the report reflects them
+	if autoFix && mr.State == "opened" && draftMode != DraftModeSilent {
+		if applied := c.applyAutoFixes(projectID, mr, failures); len(applied) > 0 {
+			result, err := c.check(projectID, mrID, nil, nil, false)
+			if err != nil {
+				return nil, err
+			}
+			result.AppliedFixes = applied
+			result.Summary = c.summaryGenerator.GenerateSummary(c.report(result, previous, configSource, draftMode), messages, finalConfig.Report.Template)
+			return result, nil
+		}
+	}
 
 	// Generate results
 	passed := len(failures) == 0
-	summary := c.summaryGenerator.GenerateSummary(failures)
+	ruleNames := make([]string, 0, len(rulesList))
+	for _, rule := range rulesList {
+		ruleNames = append(ruleNames, rule.Name())
+	}
+	result := &CheckResult{
+		Passed:      passed,
+		Failures:    failures,
+		SHA:         mr.SHA,
+		Rules:       ruleNames,
+		Silent:      draftMode == DraftModeSilent,
+		Exemptions:  exemptions,
+		Waived:      waived,
+		Fingerprint: fingerprint,
+	}
+	result.Summary = c.summaryGenerator.GenerateSummary(c.report(result, previous, configSource, draftMode), messages, finalConfig.Report.Template)
 
-	return &CheckResult{
-		Passed:   passed,
-		Failures: failures,
-		Summary:  summary,
-	}, nil
+	return result, nil
 }
 
-// fetchMergeRequestData retrieves merge request and commit data
-func (c *Checker) fetchMergeRequestData(projectID interface{}, mrID int) (*gitlabapi.MergeRequest, []*gitlabapi.Commit, *common.Approvals, error) {
-	// Get merge request details
-	mr, err := c.gitlabClient.GetMergeRequest(projectID, mrID)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to get merge request: %w", err)
+// report describes a check result for the summary, comparing it with the previous result
+func (c *Checker) report(result, previous *CheckResult, source config.ConfigSource, draftMode string) Report {
+	report := newReport(result, previous, source)
+	if draftMode == DraftModeReduced {
+		report.Draft = true
+		report.DraftRules = result.Rules
 	}
-	// Get mr approvers
-	approvals, err := c.gitlabClient.ListMergeRequestApprovals(projectID, mrID)
+	return report
+}
+
+// Messages returns the message catalogue configured for a project, the default one when the
+// configuration cannot be loaded
+func (c *Checker) Messages(projectID interface{}) *i18n.Catalog {
+	cfg, _, err := c.configLoader.LoadConfig(projectID)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to get merge request: %w", err)
+		c.logger.Warn("Failed to load configuration, using the default messages", "projectId", projectID, "error", err)
+		return c.bundle.Catalog("")
 	}
+	return c.messages(cfg.Report)
+}
 
-	// Get commits for commit-related rules
-	commits, err := c.gitlabClient.ListMergeRequestCommits(projectID, mrID)
+// messages returns the catalogue of the report language, with the messages overridden by the configuration
+func (c *Checker) messages(reportConfig config.ReportConfig) *i18n.Catalog {
+	catalog := c.bundle.Catalog(reportConfig.Language)
+	if reportConfig.Language != "" && catalog.Language() != strings.ToLower(reportConfig.Language) {
+		c.logger.Warn("Unknown report language, using the default one", "language", reportConfig.Language)
+	}
+	messages, err := catalog.With(reportConfig.Messages)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to get commits: %w", err)
+		c.logger.Warn("Invalid report messages, ignoring them", "error", err)
 	}
+	return messages
+}
 
-	return mr, commits, approvals, nil
+// ApplyFix changes the merge request field targeted by a fix
+func (c *Checker) ApplyFix(projectID interface{}, mrID int, fix rules.Fix) error {
+	switch fix.Field {
+	case rules.FixTitle:
+		return c.gitlabClient.SetMergeRequestTitle(projectID, mrID, fix.Value)
+	case rules.FixDescription:
+		return c.gitlabClient.SetMergeRequestDescription(projectID, mrID, fix.Value)
+	case rules.FixSquash:
+		squash, err := strconv.ParseBool(fix.Value)
+		if err != nil {
+			return fmt.Errorf("invalid squash value %q", fix.Value)
+		}
+		return c.gitlabClient.SetMergeRequestSquash(projectID, mrID, squash)
+	default:
+		return fmt.Errorf("the %s cannot be changed through the API", fix.Field)
+	}
+}
+
+// applyAutoFixes applies the automatic fixes of the failures, the first fix of a field winning
+func (c *Checker) applyAutoFixes(projectID interface{}, mr *gitlabapi.MergeRequest, failures []RuleFailure) []AppliedFix {
+	var applied []AppliedFix
+	fixed := make(map[rules.FixField]bool)
+
+	for _, failure := range failures {
+		for _, fix := range failure.Fixes {
+			if !fix.Auto || fixed[fix.Field] {
+				continue
+			}
+			if err := c.ApplyFix(projectID, mr.IID, fix); err != nil {
+				c.logger.Warn("Failed to apply fix", "rule", failure.RuleName, "field", fix.Field, "error", err)
+				continue
+			}
+			fixed[fix.Field] = true
+			applied = append(applied, AppliedFix{RuleName: failure.RuleName, Field: fix.Field, Previous: fieldValue(mr, fix.Field), Value: fix.Value})
+			c.logger.Info("Applied fix", "rule", failure.RuleName, "field", fix.Field, "value", fix.Value)
+		}
+	}
+
+	return applied
+}
+
+// fieldValue returns the current value of a merge request field targeted by fixes
+func fieldValue(mr *gitlabapi.MergeRequest, field rules.FixField) string {
+	switch field {
+	case rules.FixTitle:
+		return mr.Title
+	case rules.FixDescription:
+		return mr.Description
+	case rules.FixBranch:
+		return mr.SourceBranch
+	case rules.FixSquash:
+		return strconv.FormatBool(mr.Squash)
+	}
+	return ""
+}
+
+// failure returns the failure reported for a rule, nil when the rule passed
+func (r *CheckResult) failure(ruleName string) *RuleFailure {
+	for i := range r.Failures {
+		if r.Failures[i].RuleName == ruleName {
+			return &r.Failures[i]
+		}
+	}
+	return nil
+}
+
+// checkFingerprint identifies the configuration and the merge request content rules are checked
+// against, empty when it cannot be computed
+func checkFingerprint(cfg config.RulesConfig, mr *gitlabapi.MergeRequest) string {
+	content := struct {
+		Config       config.RulesConfig
+		SHA          string
+		Title        string
+		Description  string
+		SourceBranch string
+		TargetBranch string
+		Labels       []string
+		Draft        bool
+		Squash       bool
+		AuthorID     int
+	}{cfg, mr.SHA, mr.Title, mr.Description, mr.SourceBranch, mr.TargetBranch, mr.Labels, mr.Draft, mr.Squash, 0}
+	if mr.Author != nil {
+		content.AuthorID = mr.Author.ID
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // executeRuleChecks runs all rules and collects failures
-func (c *Checker) executeRuleChecks(rulesList []rules.Rule, mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, codeowners []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) []RuleFailure {
+func (c *Checker) executeRuleChecks(rulesList []rules.Rule, mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, codeowners []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff, sourceErrors map[rules.DataSource]error, reused map[string]*RuleFailure, messages *i18n.Catalog) []RuleFailure {
 	var failures []RuleFailure
 
 	for _, rule := range rulesList {
+		// Reuse the previous result, unless the rule could not be evaluated
+		if failure, ok := reused[rule.Name()]; ok && (failure == nil || !failure.Unevaluated) {
+			if failure != nil {
+				failures = append(failures, *failure)
+			}
+			continue
+		}
+
 		c.logger.Debug("Checking rule", "rule", rule.Name())
 
-		result, err := rule.Check(mr, commits, approvals, codeowners, members)
+		// Skip evaluation when a data source the rule depends on could not be fetched
+		if dependent, ok := rule.(rules.DataDependent); ok {
+			if err := firstSourceError(dependent.DataSources(), sourceErrors); err != nil {
+				failures = append(failures, newUnevaluatedFailure(rule, err, messages))
+				continue
+			}
+		}
+
+		result, err := c.runRuleCheck(rule, mr, commits, approvals, codeowners, members, diffs)
 		if err != nil {
 			c.logger.Error("Rule check failed", "rule", rule.Name(), "error", err)
+			failures = append(failures, newUnevaluatedFailure(rule, err, messages))
 			continue
 		}
 
 		if !result.Passed {
+			severity := rule.Severity()
+			if result.WarningOnly {
+				severity = rules.SeverityWarning
+			}
 			failures = append(failures, RuleFailure{
 				RuleName:   rule.Name(),
-				Severity:   rule.Severity(),
+				Severity:   severity,
 				Error:      result.Error,
 				Suggestion: result.Suggestion,
+				Fixes:      result.Fixes,
 			})
 		}
 	}
@@ -144,7 +438,97 @@ func (c *Checker) executeRuleChecks(rulesList []rules.Rule, mr *gitlabapi.MergeR
 	return failures
 }
 
-func (c *Checker) getCodeowners(projectID interface{}, mrID int, members []*gitlabapi.ProjectMember) ([]*codeowners.PatternGroup, error) {
+// runRuleCheck executes a single rule, converting panics into errors so one faulty rule cannot stop the bot
+func (c *Checker) runRuleCheck(rule rules.Rule, mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, codeowners []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (result *rules.RuleResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error("Rule check panicked", "rule", rule.Name(), "panic", r, "stack", string(debug.Stack()))
+			result = nil
+			err = fmt.Errorf("internal error: %v", r)
+		}
+	}()
+
+	return rule.Check(mr, commits, approvals, codeowners, members, diffs)
+}
+
+// firstSourceError returns the error of the first failed data source in the list
+func firstSourceError(sources []rules.DataSource, sourceErrors map[rules.DataSource]error) error {
+	for _, source := range sources {
+		if err, failed := sourceErrors[source]; failed {
+			return fmt.Errorf("failed to load %s: %w", source, err)
+		}
+	}
+	return nil
+}
+
+// newUnevaluatedFailure reports a rule that could not be evaluated
+func newUnevaluatedFailure(rule rules.Rule, err error, messages *i18n.Catalog) RuleFailure {
+	return RuleFailure{
+		RuleName:    rule.Name(),
+		Severity:    rule.Severity(),
+		Error:       []string{messages.T("report.unevaluated_error", err)},
+		Suggestion:  []string{messages.T("report.unevaluated_error_tip")},
+		Unevaluated: true,
+	}
+}
+
+// assignReviewers adds code owners as reviewers so every unapproved required pattern is covered
+func (c *Checker) assignReviewers(projectID interface{}, mr *gitlabapi.MergeRequest, co []*codeowners.PatternGroup, approvals *common.Approvals, members []*gitlabapi.ProjectMember) error {
+	summary := codeowners.CreateCodeOwnersSummary(co, approvals, members)
+	if summary.AllPatternsApproved {
+		return nil
+	}
+
+	memberIDs := make(map[string]int)
+	for _, member := range members {
+		memberIDs[strings.ToLower(member.Username)] = member.ID
+	}
+
+	var current []string
+	var reviewerIDs []int
+	for _, reviewer := range mr.Reviewers {
+		current = append(current, reviewer.Username)
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+
+	var excluded []string
+	if mr.Author != nil {
+		excluded = append(excluded, mr.Author.Username)
+	}
+
+	// Balance load using the number of open merge requests each candidate reviews
+	load := func(username string) int {
+		id, ok := memberIDs[strings.ToLower(username)]
+		if !ok {
+			return 0
+		}
+		count, err := c.gitlabClient.CountOpenReviews(id)
+		if err != nil {
+			c.logger.Debug("Failed to count open reviews", "user", username, "error", err)
+			return 0
+		}
+		return count
+	}
+
+	suggested := codeowners.SuggestReviewers(summary, excluded, current, load)
+	added := 0
+	for _, username := range suggested {
+		if id, ok := memberIDs[strings.ToLower(username)]; ok {
+			reviewerIDs = append(reviewerIDs, id)
+			added++
+		}
+	}
+
+	if added == 0 {
+		return nil
+	}
+
+	c.logger.Info("Assigning code owners as reviewers", "projectId", projectID, "mrId", mr.IID, "reviewers", suggested)
+	return c.gitlabClient.SetMergeRequestReviewers(projectID, mr.IID, reviewerIDs)
+}
+
+// getCodeowners returns the CODEOWNERS patterns applying to the changed paths
+func (c *Checker) getCodeowners(projectID interface{}, members []*gitlabapi.ProjectMember, paths []string) ([]*codeowners.PatternGroup, error) {
 	// Try to get CODEOWNERS file from repository
 	co, err := c.gitlabClient.GetCodeownersFile(projectID)
 	if err != nil {
@@ -155,25 +539,15 @@ func (c *Checker) getCodeowners(projectID interface{}, mrID int, members []*gitl
 	// Decode the base64 content
 	decoded, err := base64.StdEncoding.DecodeString(co.Content)
 	if err != nil {
-		c.logger.Warn("Failed to decode config file from repository, using default config", "error", err)
-		return nil, fmt.Errorf("failed to decode config: %w", err)
+		return nil, fmt.Errorf("failed to decode CODEOWNERS: %w", err)
 	}
 
 	parser := codeowners.NewCodeownersParser(c.logger)
-	for _, member := range members {
-		parser.AddAccessibleUser(member.Username)
-		parser.AddAccessibleRole(int(member.AccessLevel))
-		parser.AddAccessibleEmail(member.Email)
-	}
+	parser.AddAccessibleMembers(members)
 
 	cos, err := parser.Parse(strings.NewReader(string(decoded)))
 	if err != nil {
-		c.logger.Fatal("Error parsing CODEOWNERS: %v", err)
-	}
-
-	paths, err := c.gitlabClient.GetAllDiffsPaths(projectID, mrID)
-	if err != nil {
-		log.Fatalf("Error obtaining diff paths: %v", err)
+		return nil, fmt.Errorf("failed to parse CODEOWNERS: %w", err)
 	}
 
 	// Get only active patterns (final effective patterns)
@@ -0,0 +1,59 @@
+package conformity
+
+import (
+	"fmt"
+
+	"gitlab-mr-conformity-bot/internal/config"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// matchExemptions returns the names of the exemptions matching a merge request, the rules they
+// waive and whether one of them waives every rule
+func (c *Checker) matchExemptions(exemptions []config.Exemption, mr *gitlabapi.MergeRequest) ([]string, []string, bool) {
+	author, authorID := "", 0
+	if mr.Author != nil {
+		author, authorID = mr.Author.Username, mr.Author.ID
+	}
+
+	// Whether the author is a bot needs an extra API call, only made when used
+	bot := false
+	for _, exemption := range exemptions {
+		if exemption.Bot && authorID != 0 {
+			isBot, err := c.gitlabClient.IsBot(authorID)
+			if err != nil {
+				c.logger.Warn("Failed to check whether the author is a bot", "author", author, "error", err)
+			}
+			bot = isBot
+			break
+		}
+	}
+
+	var names, waived []string
+	all := false
+	for i, exemption := range exemptions {
+		matched, err := exemption.Matches(author, bot, mr.SourceBranch, mr.Labels)
+		if err != nil {
+			c.logger.Warn("Invalid exemption", "exemption", exemptionName(exemption, i), "error", err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		names = append(names, exemptionName(exemption, i))
+		if len(exemption.Rules) == 0 {
+			all = true
+		}
+		waived = append(waived, exemption.Rules...)
+	}
+
+	return names, waived, all
+}
+
+// exemptionName returns the name of an exemption, or its position when unnamed
+func exemptionName(exemption config.Exemption, index int) string {
+	if exemption.Name != "" {
+		return exemption.Name
+	}
+	return fmt.Sprintf("exemption %d", index+1)
+}
@@ -0,0 +1,138 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// compatCasesDir holds the GitLab compatibility cases: each case directory holds a CODEOWNERS
+// file, the changed paths, the project members and the approvals GitLab requires for them
+const compatCasesDir = "testdata/gitlab-compat"
+
+func TestGitLabCompatibility(t *testing.T) {
+	entries, err := os.ReadDir(compatCasesDir)
+	if err != nil {
+		t.Fatalf("failed to list compatibility cases: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(compatCasesDir, entry.Name())
+
+		t.Run(entry.Name(), func(t *testing.T) {
+			members := readMembers(t, filepath.Join(dir, "members.txt"))
+			paths := readLines(t, filepath.Join(dir, "paths.txt"))
+			expected := readLines(t, filepath.Join(dir, "expected.txt"))
+
+			file, err := os.Open(filepath.Join(dir, "CODEOWNERS"))
+			if err != nil {
+				t.Fatalf("failed to open CODEOWNERS: %v", err)
+			}
+			defer file.Close()
+
+			parser := NewCodeownersParser(logger.NewWithLevel("ERROR"))
+			parser.AddAccessibleMembers(members)
+			parsed, err := parser.Parse(file)
+			if err != nil {
+				t.Fatalf("failed to parse CODEOWNERS: %v", err)
+			}
+
+			got := requiredApprovals(parsed, paths, members)
+			if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+				t.Errorf("required approvals mismatch\n--- got\n%s\n--- want\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+			}
+		})
+	}
+}
+
+// requiredApprovals describes the approvals the changed paths require, one pattern per line
+func requiredApprovals(parsed *CODEOWNERSFile, paths []string, members []*gitlabapi.ProjectMember) []string {
+	var groups []*PatternGroup
+	for _, pg := range GetActivePatternAggregation(parsed, paths).PatternGroups {
+		groups = append(groups, pg)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].LineNumber < groups[j].LineNumber
+	})
+
+	var lines []string
+	for _, pattern := range CreateCodeOwnersSummary(groups, nil, members).Patterns {
+		var requirement string
+		switch {
+		case pattern.IsOptional:
+			requirement = "optional"
+		case pattern.IsAutoApproved:
+			requirement = "auto-approved"
+		default:
+			approvers := make([]string, len(pattern.AllowedApprovers))
+			for i, approver := range pattern.AllowedApprovers {
+				approvers[i] = "@" + approver
+			}
+			sort.Strings(approvers)
+			requirement = fmt.Sprintf("%d of %s", pattern.RequiredCount, strings.Join(approvers, ", "))
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s (line %d): %s", pattern.Pattern.SectionName, pattern.Pattern.Pattern, pattern.Pattern.LineNumber, requirement))
+	}
+	return lines
+}
+
+// readMembers parses "username access_level [email]" lines into project members
+func readMembers(t *testing.T, path string) []*gitlabapi.ProjectMember {
+	t.Helper()
+	var members []*gitlabapi.ProjectMember
+	for _, line := range readLines(t, path) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			t.Fatalf("invalid member entry: %q", line)
+		}
+		level, err := strconv.Atoi(fields[1])
+		if err != nil {
+			t.Fatalf("invalid access level in %q: %v", line, err)
+		}
+		member := &gitlabapi.ProjectMember{
+			Username:    fields[0],
+			AccessLevel: gitlabapi.AccessLevelValue(level),
+		}
+		if len(fields) > 2 {
+			member.Email = fields[2]
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// readLines returns the non-empty, non-comment lines of a file
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return lines
+}
@@ -0,0 +1,185 @@
+package codeowners
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Lint inspects a parsed CODEOWNERS file and reports syntax errors, unknown owners,
+// unreachable patterns and sections whose approval count cannot be satisfied.
+// Owner checks are only performed when project members are provided.
+func Lint(c *CODEOWNERSFile, members []*gitlabapi.ProjectMember) []LintIssue {
+	var issues []LintIssue
+
+	for _, parseErr := range c.ParseErrors {
+		issues = append(issues, LintIssue{
+			Kind:    LintSyntaxError,
+			Message: parseErr,
+		})
+	}
+
+	issues = append(issues, lintRules(c.DefaultRules, nil, "Default", members)...)
+
+	for i := range c.Sections {
+		section := &c.Sections[i]
+		if section.ParseError != "" {
+			issues = append(issues, LintIssue{
+				Kind:        LintSyntaxError,
+				SectionName: section.Name,
+				LineNumber:  section.LineNumber,
+				Message:     section.ParseError,
+			})
+		}
+		if len(members) > 0 {
+			for _, owner := range section.DefaultOwners {
+				if !owner.IsValid {
+					issues = append(issues, LintIssue{
+						Kind:        LintUnknownOwner,
+						SectionName: section.Name,
+						LineNumber:  section.LineNumber,
+						Message:     fmt.Sprintf("unknown section default owner %s", owner.Original),
+					})
+				}
+			}
+		}
+		issues = append(issues, lintRules(section.Rules, section, section.Name, members)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].LineNumber < issues[j].LineNumber
+	})
+
+	return issues
+}
+
+// lintRules checks the rules of a single section (or the default rules)
+func lintRules(rules []Rule, section *Section, sectionName string, members []*gitlabapi.ProjectMember) []LintIssue {
+	var issues []LintIssue
+
+	for i, rule := range rules {
+		if rule.HasParseError && rule.ParseError != "" {
+			issues = append(issues, LintIssue{
+				Kind:        LintSyntaxError,
+				SectionName: sectionName,
+				LineNumber:  rule.LineNumber,
+				Message:     rule.ParseError,
+			})
+		}
+
+		if len(members) > 0 {
+			for _, owner := range rule.Owners {
+				if !owner.IsValid {
+					issues = append(issues, LintIssue{
+						Kind:        LintUnknownOwner,
+						SectionName: sectionName,
+						LineNumber:  rule.LineNumber,
+						Message:     fmt.Sprintf("unknown owner %s", owner.Original),
+					})
+				}
+			}
+		}
+
+		// Later rules take precedence, so an earlier rule fully covered by a later one never applies
+		if !rule.IsExclusion {
+			for _, later := range rules[i+1:] {
+				if patternShadows(later.Pattern, rule.Pattern) {
+					issues = append(issues, LintIssue{
+						Kind:        LintUnreachablePattern,
+						SectionName: sectionName,
+						LineNumber:  rule.LineNumber,
+						Message:     fmt.Sprintf("pattern %q is shadowed by %q on line %d", rule.Pattern, later.Pattern, later.LineNumber),
+					})
+					break
+				}
+			}
+		}
+
+		if section != nil && !rule.IsExclusion && !rule.HasParseError {
+			eligible, known := countEligibleApprovers(rule, section, members)
+			if known && eligible > 0 && eligible < section.RequiredApprovals {
+				issues = append(issues, LintIssue{
+					Kind:        LintImpossibleApprovals,
+					SectionName: sectionName,
+					LineNumber:  rule.LineNumber,
+					Message: fmt.Sprintf("section [%s] requires %d approvals but pattern %q has only %d eligible approver(s)",
+						section.Name, section.RequiredApprovals, rule.Pattern, eligible),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// patternShadows reports whether every file matched by earlier is also matched by later.
+// The check is conservative: it only reports cases that can be proven statically.
+func patternShadows(later, earlier string) bool {
+	if later == earlier || isCatchAllPattern(later) {
+		return true
+	}
+
+	// A directory pattern covers every path containing that directory
+	if strings.HasSuffix(later, "/") {
+		dir := strings.Trim(later, "/")
+		if strings.ContainsAny(dir, "*?[{") {
+			return false
+		}
+		if strings.HasPrefix(later, "/") {
+			return strings.HasPrefix(earlier, "/"+dir+"/")
+		}
+		return strings.Contains("/"+strings.TrimPrefix(earlier, "/"), "/"+dir+"/")
+	}
+
+	// Wildcard and directory patterns cannot be compared reliably
+	if strings.ContainsAny(earlier, "*?[{") || strings.HasSuffix(earlier, "/") {
+		return false
+	}
+
+	matcher := NewPatternMatcher()
+	if strings.HasPrefix(earlier, "/") {
+		return matcher.MatchesPattern(later, earlier)
+	}
+
+	// A relative literal matches at any depth, so only a relative later pattern can cover it
+	return !strings.HasPrefix(later, "/") && matcher.MatchesPattern(later, earlier)
+}
+
+// isCatchAllPattern reports whether a pattern matches every file in the repository
+func isCatchAllPattern(pattern string) bool {
+	rest := strings.Trim(strings.ReplaceAll(normalizePattern(pattern), "/**", ""), "/")
+	return rest == "" || rest == "*"
+}
+
+// countEligibleApprovers returns the number of distinct users able to approve a rule and
+// whether that number could be determined (groups cannot be resolved without extra privileges)
+func countEligibleApprovers(rule Rule, section *Section, members []*gitlabapi.ProjectMember) (int, bool) {
+	owners := rule.Owners
+	if len(owners) == 0 {
+		owners = section.DefaultOwners
+	}
+
+	eligible := make(map[string]bool)
+	for _, owner := range owners {
+		if len(members) > 0 && !owner.IsValid {
+			continue
+		}
+		switch {
+		case owner.IsRole:
+			if len(members) == 0 {
+				return 0, false
+			}
+			for _, username := range getRoleMembers(owner.Name, members) {
+				eligible[strings.ToLower(username)] = true
+			}
+		case owner.IsNested:
+			return 0, false
+		default:
+			eligible[strings.ToLower(owner.Name)] = true
+		}
+	}
+
+	return len(eligible), true
+}
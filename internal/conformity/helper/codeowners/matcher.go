@@ -28,17 +28,12 @@ func (c *CODEOWNERSFile) GetOwnersForFile(filePath string) map[string]SectionOwn
 		owners, isAutoApproved, validationErrors, usedDefaultOwners, matchingPatterns := c.getMatchingOwnersWithPatternsAndValidation(section.Rules, filePath, &section, section.Name)
 
 		if len(owners) > 0 || isAutoApproved || len(matchingPatterns) > 0 {
-			// Determine the correct required approvals
-			requiredApprovals := 1 // Default for explicit owners
-			if usedDefaultOwners {
-				// Only use section's required approvals if we're using section default owners
-				requiredApprovals = section.RequiredApprovals
-			}
-
+			// The section approval count applies to every entry of the section,
+			// whether it uses explicit owners or the section default owners
 			result[section.Name] = SectionOwnership{
 				Name:              section.Name,
 				Owners:            owners,
-				RequiredApprovals: requiredApprovals,
+				RequiredApprovals: section.RequiredApprovals,
 				IsOptional:        section.IsOptional,
 				IsAutoApproved:    isAutoApproved,
 				ValidationErrors:  validationErrors,
@@ -85,6 +80,14 @@ func (c *CODEOWNERSFile) getMatchingOwnersWithPatternsAndValidation(rules []Rule
 				IsActive:    false,
 			}
 
+			// Exclusions take precedence over every other pattern of the section
+			if excluded {
+				exclusion := matchingPatterns[activePatternIndex]
+				matchingPattern.OverriddenBy = &exclusion
+				matchingPatterns = append(matchingPatterns, matchingPattern)
+				continue
+			}
+
 			// If there was a previous active pattern, mark it as overridden
 			if activePatternIndex >= 0 {
 				matchingPatterns[activePatternIndex].IsActive = false
@@ -100,7 +103,7 @@ func (c *CODEOWNERSFile) getMatchingOwnersWithPatternsAndValidation(rules []Rule
 				isAutoApproved = false
 				usedDefaultOwners = false
 				matchingPatterns[activePatternIndex].IsActive = true
-			} else if !excluded {
+			} else {
 				matchingPatterns[activePatternIndex].IsActive = true
 
 				// FIXED: Check if rule has parsing errors first
@@ -109,7 +112,8 @@ func (c *CODEOWNERSFile) getMatchingOwnersWithPatternsAndValidation(rules []Rule
 					isAutoApproved = true
 					matchedOwners = nil
 					usedDefaultOwners = false
-				} else if rule.HasZeroOwners {
+				} else if len(rule.Owners) == 0 {
+					// Only entries without any listed owner inherit the section default owners
 					// Check if we have section default owners to use
 					if section != nil && len(section.DefaultOwners) > 0 {
 						// Use section default owners
@@ -147,8 +151,9 @@ func (c *CODEOWNERSFile) getMatchingOwnersWithPatternsAndValidation(rules []Rule
 					usedDefaultOwners = false
 
 					// Pass parse errors to validation errors
-					validationErrors = append(validationErrors, rule.ParseError)
-
+					if rule.ParseError != "" {
+						validationErrors = append(validationErrors, rule.ParseError)
+					}
 				}
 			}
 		}
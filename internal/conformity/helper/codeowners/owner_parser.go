@@ -3,6 +3,8 @@ package codeowners
 import (
 	"fmt"
 	"strings"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
 // parseOwner parses a single owner specification with enhanced validation
@@ -231,6 +233,15 @@ func (p *Parser) AddAccessibleRoleByName(rolename string) {
 	p.accessibleOwners.Roles[cleanRole] = true
 }
 
+// AddAccessibleMembers registers project members as accessible users, roles and emails
+func (p *Parser) AddAccessibleMembers(members []*gitlabapi.ProjectMember) {
+	for _, member := range members {
+		p.AddAccessibleUser(member.Username)
+		p.AddAccessibleRole(int(member.AccessLevel))
+		p.AddAccessibleEmail(member.Email)
+	}
+}
+
 // AddAccessibleEmail adds an email to the accessible owners
 func (p *Parser) AddAccessibleEmail(email string) {
 	p.accessibleOwners.Emails[email] = true
@@ -0,0 +1,98 @@
+package codeowners
+
+import (
+	"sort"
+	"strings"
+)
+
+// reviewerNeed tracks the approvals still missing for one required pattern
+type reviewerNeed struct {
+	candidates map[string]bool
+	remaining  int
+}
+
+// SuggestReviewers picks a minimal set of users covering the remaining approvals of every
+// unapproved required pattern. Current reviewers are counted first, excluded users (e.g. the
+// author) are never suggested, and ties are broken by the lowest open review load.
+func SuggestReviewers(summary *CodeOwnersSummary, excluded []string, current []string, load func(username string) int) []string {
+	skip := make(map[string]bool)
+	for _, username := range excluded {
+		skip[strings.ToLower(username)] = true
+	}
+
+	// Users who already approved cannot contribute further approvals
+	approvals := summary.getApprovals()
+	for _, approval := range approvals.ApprovalsInfo {
+		if approval.Status == "approved" {
+			skip[strings.ToLower(approval.Username)] = true
+		}
+	}
+
+	var needs []*reviewerNeed
+	usernames := make(map[string]string)
+	for _, pattern := range summary.Patterns {
+		if pattern.IsFullyApproved || pattern.IsOptional || pattern.IsAutoApproved || pattern.IsExclusion {
+			continue
+		}
+		need := &reviewerNeed{candidates: make(map[string]bool), remaining: pattern.RemainingCount}
+		for _, approver := range pattern.AllowedApprovers {
+			key := strings.ToLower(approver)
+			if skip[key] {
+				continue
+			}
+			need.candidates[key] = true
+			usernames[key] = approver
+		}
+		needs = append(needs, need)
+	}
+
+	// Existing reviewers cover what they can before anyone new is suggested
+	for _, username := range current {
+		assignReviewer(needs, strings.ToLower(username))
+	}
+
+	loads := make(map[string]int)
+	var suggested []string
+	for {
+		best := ""
+		bestScore := 0
+		for key := range usernames {
+			score := 0
+			for _, need := range needs {
+				if need.remaining > 0 && need.candidates[key] {
+					score++
+				}
+			}
+			if score == 0 {
+				continue
+			}
+			if _, ok := loads[key]; !ok {
+				loads[key] = load(usernames[key])
+			}
+			if score > bestScore || (score == bestScore && (loads[key] < loads[best] || (loads[key] == loads[best] && key < best))) {
+				best = key
+				bestScore = score
+			}
+		}
+
+		if best == "" {
+			break
+		}
+
+		assignReviewer(needs, best)
+		suggested = append(suggested, usernames[best])
+	}
+
+	sort.Strings(suggested)
+	return suggested
+}
+
+// assignReviewer counts a reviewer towards every need they can approve
+func assignReviewer(needs []*reviewerNeed, key string) {
+	for _, need := range needs {
+		if need.remaining > 0 && need.candidates[key] {
+			need.remaining--
+			delete(need.candidates, key)
+		}
+	}
+}
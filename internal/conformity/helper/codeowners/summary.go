@@ -1,19 +1,51 @@
 package codeowners
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 	"sort"
 	"strings"
+	"text/template"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
+//go:embed templates/approvals.md.tmpl
+var templates embed.FS
+
+// approvalsTemplate renders the approvals of code owners, its `t` function translating messages
+var approvalsTemplate = template.Must(template.New("approvals.md.tmpl").Funcs(template.FuncMap{
+	"t":    i18n.Default().T,
+	"join": strings.Join,
+	"trim": strings.TrimSpace,
+	"patterns": func(patterns []string) string {
+		quoted := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			quoted[i] = fmt.Sprintf("``%s``", pattern)
+		}
+		return strings.Join(quoted, "<br>")
+	},
+}).ParseFS(templates, "templates/approvals.md.tmpl"))
+
+// renderApprovals executes a template of the approvals with the messages of a catalogue
+func renderApprovals(name string, data interface{}, messages *i18n.Catalog) string {
+	tmpl := template.Must(approvalsTemplate.Clone()).Funcs(template.FuncMap{"t": messages.T})
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return fmt.Sprintf("failed to render code owner approvals: %v", err)
+	}
+	return buf.String()
+}
+
 // Main function to create the summary - now accepts members parameter
 func CreateCodeOwnersSummary(codeowners []*PatternGroup, approvals *common.Approvals, members []*gitlabapi.ProjectMember) *CodeOwnersSummary {
 	summary := &CodeOwnersSummary{
-		Patterns: make([]PatternApprovalSummary, 0, len(codeowners)),
-		Members:  members,
+		Patterns:  make([]PatternApprovalSummary, 0, len(codeowners)),
+		Members:   members,
+		Approvals: approvals,
 	}
 
 	for _, pattern := range codeowners {
@@ -68,66 +100,15 @@ func createPatternSummary(pattern *PatternGroup, approvals *common.Approvals, me
 		return summary
 	}
 
-	// If the pattern is auto-approved or optional, mark it as fully approved immediately
-	if pattern.IsAutoApproved || pattern.IsOptional {
-		summary.IsFullyApproved = true
-		summary.ApprovedCount = summary.RequiredCount // Set to required count to show as satisfied
-		summary.RemainingCount = 0
-
-		// Still process owners for display purposes
-		for _, owner := range pattern.Owners {
-			ownerStatus := OwnerApprovalStatus{
-				Owner: owner,
-			}
-
-			// Find if this owner has approved (for display)
-			if approvals != nil && approvals.ApprovalsInfo != nil {
-				for userID, approval := range approvals.ApprovalsInfo {
-					if matchesOwner(owner, approval, members) {
-						ownerStatus.HasApproved = (approval.Status == "approved")
-						ownerStatus.ApprovalInfo = &common.ApprovalInfo{
-							UserID:    userID,
-							Username:  approval.Username,
-							Status:    approval.Status,
-							UpdatedAt: approval.UpdatedAt,
-						}
-						break
-					}
-				}
-			}
-
-			summary.OwnerStatuses = append(summary.OwnerStatuses, ownerStatus)
+	// Build allowed approvers list - roles and emails are expanded to member usernames
+	summary.AllowedApprovers = resolveAllowedApprovers(pattern.Owners, members)
 
-			// Add to allowed approvers list - expand roles to individual members
-			if owner.IsRole {
-				roleMembers := getRoleMembers(owner.Name, members)
-				summary.AllowedApprovers = append(summary.AllowedApprovers, roleMembers...)
-			} else {
-				// Since owners are pre-filtered to accessible members, we can directly add them
-				summary.AllowedApprovers = append(summary.AllowedApprovers, owner.Name)
-			}
-		}
-
-		return summary
-	}
-
-	// Build allowed approvers list - since owners are pre-filtered, all should be valid
-	for _, owner := range pattern.Owners {
-		if owner.IsRole {
-			roleMembers := getRoleMembers(owner.Name, members)
-			summary.AllowedApprovers = append(summary.AllowedApprovers, roleMembers...)
-		} else {
-			summary.AllowedApprovers = append(summary.AllowedApprovers, owner.Name)
-		}
-	}
-
-	// Check each owner's approval status for regular patterns
+	// Check each owner's approval status (for display)
 	for _, owner := range pattern.Owners {
 		ownerStatus := OwnerApprovalStatus{
 			Owner: owner,
 		}
 
-		// Find if this owner has approved
 		if approvals != nil && approvals.ApprovalsInfo != nil {
 			for userID, approval := range approvals.ApprovalsInfo {
 				if matchesOwner(owner, approval, members) {
@@ -138,11 +119,6 @@ func createPatternSummary(pattern *PatternGroup, approvals *common.Approvals, me
 						Status:    approval.Status,
 						UpdatedAt: approval.UpdatedAt,
 					}
-
-					// Count valid approvals - since owners are pre-filtered, all approvals are valid
-					if approval.Status == "approved" {
-						summary.ApprovedCount++
-					}
 					break
 				}
 			}
@@ -151,12 +127,57 @@ func createPatternSummary(pattern *PatternGroup, approvals *common.Approvals, me
 		summary.OwnerStatuses = append(summary.OwnerStatuses, ownerStatus)
 	}
 
+	// If the pattern is auto-approved or optional, mark it as fully approved immediately
+	if pattern.IsAutoApproved || pattern.IsOptional {
+		summary.IsFullyApproved = true
+		summary.ApprovedCount = summary.RequiredCount // Set to required count to show as satisfied
+		summary.RemainingCount = 0
+		return summary
+	}
+
+	// Every eligible user counts once, even when matched by several owners (e.g. a user and their role)
+	summary.ApprovedCount = countApprovalsForOwners(summary.AllowedApprovers, approvals)
 	summary.RemainingCount = max(0, summary.RequiredCount-summary.ApprovedCount)
 	summary.IsFullyApproved = summary.ApprovedCount >= summary.RequiredCount
 
 	return summary
 }
 
+// resolveAllowedApprovers expands owners to the distinct usernames allowed to approve
+func resolveAllowedApprovers(owners []Owner, members []*gitlabapi.ProjectMember) []string {
+	var approvers []string
+	seen := make(map[string]bool)
+
+	for _, owner := range owners {
+		for _, username := range resolveOwnerUsernames(owner, members) {
+			key := strings.ToLower(username)
+			if !seen[key] {
+				seen[key] = true
+				approvers = append(approvers, username)
+			}
+		}
+	}
+
+	return approvers
+}
+
+// resolveOwnerUsernames maps a single owner to the usernames it stands for
+func resolveOwnerUsernames(owner Owner, members []*gitlabapi.ProjectMember) []string {
+	if owner.IsRole {
+		return getRoleMembers(owner.Name, members)
+	}
+
+	if owner.IsEmail {
+		for _, member := range members {
+			if strings.EqualFold(member.Email, owner.Name) {
+				return []string{member.Username}
+			}
+		}
+	}
+
+	return []string{owner.Name}
+}
+
 // common function to get members that belong to a specific role based on access level
 func getRoleMembers(roleName string, members []*gitlabapi.ProjectMember) []string {
 	if members == nil {
@@ -206,32 +227,17 @@ func getRequiredAccessLevel(roleName string) int {
 
 // Simplified common function to match owner with approval - uses Owner struct properties
 func matchesOwner(owner Owner, approval common.ApprovalInfo, members []*gitlabapi.ProjectMember) bool {
-	// Handle email matching
-	if owner.IsEmail {
-		return strings.EqualFold(owner.Name, approval.Username) ||
-			strings.Contains(strings.ToLower(approval.Username), strings.ToLower(owner.Name))
-	}
-
-	// Handle role matching - use the IsRole property from Owner struct
-	if owner.IsRole {
-		roleMembers := getRoleMembers(owner.Name, members)
-		for _, member := range roleMembers {
-			if strings.EqualFold(member, approval.Username) {
-				return true
-			}
+	// Roles and emails are resolved to member usernames
+	for _, username := range resolveOwnerUsernames(owner, members) {
+		if strings.EqualFold(username, approval.Username) {
+			return true
 		}
-		return false
 	}
-
-	// For individual usernames
-	return strings.EqualFold(owner.Name, approval.Username)
+	return false
 }
 
 // Generate aggregated markdown table with merged sections (by section name AND owners)
-func (s *CodeOwnersSummary) GenerateAggregatedOutput() (string, string) {
-	var aggregatedTable strings.Builder
-	var needsApprovals bool
-
+func (s *CodeOwnersSummary) GenerateAggregatedOutput(messages *i18n.Catalog) (string, string) {
 	// Group patterns by section name AND owners signature
 	sectionMap := make(map[string]*MergedSectionSummary)
 	sectionOrder := []string{}
@@ -297,82 +303,26 @@ func (s *CodeOwnersSummary) GenerateAggregatedOutput() (string, string) {
 		section.IsFullyApproved = section.ApprovedCount >= section.RequiredCount || isAutoApproved || len(section.AllowedApprovers) == 0
 	}
 
-	// Build the complete table with merged sections
-	aggregatedTable.WriteString("\n\n| | Code owners | Approvals | Allowed approvers |\n")
-	aggregatedTable.WriteString("| --- | --- | --- | --- |\n")
-
+	// Render the table with merged sections, and the suggestion
+	var needsApprovals bool
+	sections := make([]*MergedSectionSummary, 0, len(sectionOrder))
 	for _, groupKey := range sectionOrder {
 		section := sectionMap[groupKey]
-
-		// Set checkbox based on approval status
-		checkbox := "[ ]"
-		if section.IsFullyApproved {
-			checkbox = "[x]"
-		}
-
-		// Format patterns
-		var patternsDisplay string
-		if len(section.Patterns) == 1 {
-			patternsDisplay = fmt.Sprintf("``%s``", section.Patterns[0])
-		} else {
-			patternsList := make([]string, len(section.Patterns))
-			for i, pattern := range section.Patterns {
-				patternsList[i] = fmt.Sprintf("``%s``", pattern)
-			}
-			patternsDisplay = strings.Join(patternsList, "<br>")
-		}
-
 		for i, val := range section.AllowedApprovers {
 			section.AllowedApprovers[i] = "@" + val
 		}
-
-		var approvals string
-		if section.IsOptional {
-			approvals = "Optional"
-		} else if section.IsAutoApproved {
-			approvals = "Auto-approved"
-		} else {
-			approvals = fmt.Sprintf("%d of %d", section.ApprovedCount, section.RequiredCount)
-		}
-		// Add section row
-		aggregatedTable.WriteString(fmt.Sprintf(
-			"|<ul><li>%s </li></ul>| <sub>%s</sub><br>%s | %s | %s |\n",
-			checkbox,
-			section.SectionName,
-			patternsDisplay,
-			approvals,
-			strings.Join(section.AllowedApprovers, ", "),
-		))
-
 		if !section.IsFullyApproved {
 			needsApprovals = true
 		}
+		sections = append(sections, section)
 	}
 
-	// Return aggregated table and suggestion
-	suggestion := ""
-	if needsApprovals {
-		suggestion = "Wait for required approvals before merging\n"
+	data := map[string]interface{}{
+		"Sections":         sections,
+		"NeedsApprovals":   needsApprovals,
+		"ValidationErrors": validationErrors,
 	}
-
-	totalErrors := 0
-	for _, ve := range validationErrors {
-		totalErrors += len(ve.Errors)
-	}
-
-	if totalErrors > 0 {
-		suggestion += "\n> **🚨 Syntax errors:**\n"
-
-		for _, ve := range validationErrors {
-			// Process each individual error string
-			for _, err := range ve.Errors {
-				if strings.TrimSpace(err) != "" { // Skip empty strings
-					suggestion += fmt.Sprintf("> - Line %d: %+v\n", ve.LineNumber, strings.TrimSpace(err))
-				}
-			}
-		}
-	}
-	return aggregatedTable.String(), suggestion
+	return renderApprovals("table", data, messages), renderApprovals("suggestion", data, messages)
 }
 
 // common function to count approvals for specific owners
@@ -402,6 +352,10 @@ func matchesApprover(approver string, approval common.ApprovalInfo) bool {
 
 // common function to get approvals from summary
 func (s *CodeOwnersSummary) getApprovals() *common.Approvals {
+	if s.Approvals != nil {
+		return s.Approvals
+	}
+
 	approvals := &common.Approvals{
 		ApprovalsInfo: make(map[int]common.ApprovalInfo),
 	}
@@ -420,7 +374,7 @@ func (s *CodeOwnersSummary) getApprovals() *common.Approvals {
 
 // Generate markdown table for all patterns (keeping for backward compatibility)
 func (s *CodeOwnersSummary) GenerateMarkdownTable() []string {
-	aggregatedError, suggestion := s.GenerateAggregatedOutput()
+	aggregatedError, suggestion := s.GenerateAggregatedOutput(nil)
 
 	results := []string{aggregatedError}
 	if suggestion != "" {
@@ -140,6 +140,7 @@ type CodeOwnersSummary struct {
 	TotalRequired       int
 	AllPatternsApproved bool
 	Members             []*gitlabapi.ProjectMember
+	Approvals           *common.Approvals
 }
 
 // Merged section summary for grouping patterns by section AND owners
@@ -243,3 +244,21 @@ type ValidationErrors struct {
 	LineNumber int
 	Errors     []string
 }
+
+// LintIssueKind classifies a CODEOWNERS lint finding
+type LintIssueKind string
+
+const (
+	LintSyntaxError         LintIssueKind = "syntax_error"
+	LintUnknownOwner        LintIssueKind = "unknown_owner"
+	LintUnreachablePattern  LintIssueKind = "unreachable_pattern"
+	LintImpossibleApprovals LintIssueKind = "impossible_approvals"
+)
+
+// LintIssue represents a single problem found while linting a CODEOWNERS file
+type LintIssue struct {
+	Kind        LintIssueKind
+	SectionName string
+	LineNumber  int
+	Message     string
+}
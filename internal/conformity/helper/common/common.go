@@ -2,6 +2,7 @@ package common
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -12,11 +13,6 @@ var (
 	JiraRegex   = regexp.MustCompile(`.*\s\[?([A-Z0-9]+)-[1-9]\d*\]?.*`)
 )
 
-func ParseHeader(msg string) []string {
-	header := strings.Split(strings.TrimPrefix(msg, "\n"), "\n")[0]
-	return HeaderRegex.FindStringSubmatch(header)
-}
-
 func Contains(slice []string, value string) bool {
 	for _, elem := range slice {
 		if elem == value {
@@ -46,3 +42,54 @@ type Approvals struct {
 	ApprovalsCount int
 	ApprovalsInfo  map[int]ApprovalInfo
 }
+
+// Approvers returns the sorted usernames of the users currently approving, leaving out those
+// who withdrew their approval
+func (a *Approvals) Approvers() []string {
+	approvers := []string{}
+	if a == nil {
+		return approvers
+	}
+	for _, info := range a.ApprovalsInfo {
+		if info.Status == "approved" {
+			approvers = append(approvers, info.Username)
+		}
+	}
+	sort.Strings(approvers)
+	return approvers
+}
+
+// Diff is the diff of a file changed by a merge request
+type Diff struct {
+	OldPath       string `json:"old_path"`
+	NewPath       string `json:"new_path"`
+	Diff          string `json:"diff"`
+	NewFile       bool   `json:"new_file"`
+	RenamedFile   bool   `json:"renamed_file"`
+	DeletedFile   bool   `json:"deleted_file"`
+	GeneratedFile bool   `json:"generated_file"`
+	Collapsed     bool   `json:"collapsed"` // Content left out by GitLab as the diff is large
+	TooLarge      bool   `json:"too_large"` // Content left out by GitLab as the diff exceeds its limits
+}
+
+// Path returns the path of the file, the old one for deleted files
+func (d *Diff) Path() string {
+	if d.DeletedFile {
+		return d.OldPath
+	}
+	return d.NewPath
+}
+
+// Truncated reports whether GitLab left out the content of the diff
+func (d *Diff) Truncated() bool {
+	return d.Collapsed || d.TooLarge
+}
+
+// DiffPaths returns the paths of the changed files
+func DiffPaths(diffs []*Diff) []string {
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		paths = append(paths, diff.Path())
+	}
+	return paths
+}
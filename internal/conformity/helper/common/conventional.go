@@ -0,0 +1,125 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FooterRegex matches the first line of a footer: a token followed by `: ` or ` #`.
+// Tokens use `-` in place of whitespace, except for BREAKING CHANGE.
+var FooterRegex = regexp.MustCompile(`^(BREAKING CHANGE|[\w-]+)(: | #)(.*)$`)
+
+// ConventionalCommit is a commit message parsed according to Conventional Commits 1.0.0
+type ConventionalCommit struct {
+	Header      string
+	Type        string
+	Scope       string
+	Breaking    bool // `!` before the colon of the header
+	Description string
+	Body        string
+	Footers     []Footer
+	// BlankLineAfterHeader is false when the line following the header is not empty
+	BlankLineAfterHeader bool
+}
+
+// Footer is a trailer of a commit message, such as `Refs: #123` or `Signed-off-by: Jane <jane@example.com>`
+type Footer struct {
+	Token string
+	Value string
+}
+
+// ParseConventionalCommit parses a commit message, returning an error when the header is not a valid
+// Conventional Commits header. The last paragraph of the message is read as footers when its
+// first line is a footer.
+func ParseConventionalCommit(msg string) (*ConventionalCommit, error) {
+	lines := strings.Split(strings.TrimPrefix(strings.ReplaceAll(msg, "\r\n", "\n"), "\n"), "\n")
+
+	groups := HeaderRegex.FindStringSubmatch(lines[0])
+	if len(groups) != 7 {
+		return nil, fmt.Errorf("invalid Conventional Commit header: %q", lines[0])
+	}
+
+	commit := &ConventionalCommit{
+		Header:               lines[0],
+		Type:                 groups[1],
+		Scope:                groups[3],
+		Breaking:             groups[4] == "!",
+		Description:          groups[5],
+		BlankLineAfterHeader: len(lines) < 2 || strings.TrimSpace(lines[1]) == "",
+	}
+
+	commit.Body, commit.Footers = splitBodyAndFooters(lines[1:])
+
+	return commit, nil
+}
+
+// ParseTrailers returns the footers of any commit message, Conventional Commit or not
+func ParseTrailers(msg string) []Footer {
+	lines := strings.Split(strings.TrimPrefix(strings.ReplaceAll(msg, "\r\n", "\n"), "\n"), "\n")
+	_, footers := splitBodyAndFooters(lines[1:])
+	return footers
+}
+
+// splitBodyAndFooters splits the lines following a header, footers forming the last paragraph
+func splitBodyAndFooters(lines []string) (string, []Footer) {
+	rest := strings.TrimRight(strings.Join(lines, "\n"), "\n ")
+	rest = strings.TrimLeft(rest, "\n")
+	if rest == "" {
+		return "", nil
+	}
+
+	var footers []Footer
+	paragraphs := strings.Split(rest, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if FooterRegex.MatchString(strings.SplitN(last, "\n", 2)[0]) {
+		footers = parseFooters(last)
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+
+	return strings.TrimSpace(strings.Join(paragraphs, "\n\n")), footers
+}
+
+// parseFooters parses a footer paragraph, lines not starting a footer continue the previous one
+func parseFooters(paragraph string) []Footer {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if groups := FooterRegex.FindStringSubmatch(line); groups != nil {
+			value := groups[3]
+			if groups[2] == " #" {
+				value = "#" + value
+			}
+			footers = append(footers, Footer{Token: groups[1], Value: value})
+			continue
+		}
+		if len(footers) > 0 {
+			footers[len(footers)-1].Value += "\n" + line
+		}
+	}
+	return footers
+}
+
+// HasBreakingChangeFooter reports whether the commit has a BREAKING CHANGE (or BREAKING-CHANGE) footer
+func (c *ConventionalCommit) HasBreakingChangeFooter() bool {
+	for _, footer := range c.Footers {
+		if IsBreakingChangeToken(footer.Token) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFooter reports whether the commit has a footer with the token, compared case-insensitively
+func (c *ConventionalCommit) HasFooter(token string) bool {
+	for _, footer := range c.Footers {
+		if strings.EqualFold(footer.Token, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBreakingChangeToken reports whether a footer token announces a breaking change
+func IsBreakingChangeToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
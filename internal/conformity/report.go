@@ -0,0 +1,118 @@
+package conformity
+
+import (
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/rules"
+)
+
+// RuleStatus is the outcome of a rule in a check
+type RuleStatus string
+
+const (
+	StatusPass        RuleStatus = "pass"
+	StatusWarn        RuleStatus = "warn"
+	StatusFail        RuleStatus = "fail"
+	StatusWaived      RuleStatus = "waived"      // Waived by an exemption
+	StatusUnevaluated RuleStatus = "unevaluated" // Could not be evaluated
+)
+
+// Report holds what the compliance report of a merge request shows
+type Report struct {
+	Failures     []RuleFailure
+	Applied      []AppliedFix // Fixes applied automatically before the check
+	Rules        []RuleState  // Status of every enabled rule, evaluated ones first
+	Changes      []RuleChange // Rules whose status changed since the previous check
+	PreviousSHA  string       // Head commit of the previous check, when changes are reported
+	Exempted     bool         // Every rule is waived by the exemptions
+	Exemptions   []string     // Names of the exemptions matching the merge request
+	Waived       []string     // Names of the rules waived by the exemptions
+	Draft        bool         // Draft checked in reduced mode
+	DraftRules   []string     // Names of the rules checked on the draft
+	SHA          string       // Head commit checked
+	ConfigSource config.ConfigSource
+}
+
+// RuleState is the status of a rule in a report
+type RuleState struct {
+	Name   string
+	Status RuleStatus
+}
+
+// RuleChange is a rule whose status changed between two checks. The status is empty on the side
+// where the rule was not enabled.
+type RuleChange struct {
+	Name     string
+	Previous RuleStatus
+	Current  RuleStatus
+}
+
+// newReport describes a check result, along with the rules whose status changed since the previous
+// result when given
+func newReport(result *CheckResult, previous *CheckResult, source config.ConfigSource) Report {
+	report := Report{
+		Failures:     result.Failures,
+		Applied:      result.AppliedFixes,
+		Rules:        result.ruleStates(),
+		Exemptions:   result.Exemptions,
+		Waived:       result.Waived,
+		SHA:          result.SHA,
+		ConfigSource: source,
+	}
+
+	// Results without rules, such as skipped drafts, cannot be compared
+	if previous != nil && len(previous.Rules)+len(previous.Waived) > 0 {
+		report.Changes = statusChanges(previous.ruleStates(), report.Rules)
+		if len(report.Changes) > 0 {
+			report.PreviousSHA = previous.SHA
+		}
+	}
+	return report
+}
+
+// ruleStates returns the status of every rule of a result, evaluated rules first
+func (r *CheckResult) ruleStates() []RuleState {
+	states := make([]RuleState, 0, len(r.Rules)+len(r.Waived))
+	for _, name := range r.Rules {
+		states = append(states, RuleState{Name: name, Status: failureStatus(r.failure(name))})
+	}
+	for _, name := range r.Waived {
+		states = append(states, RuleState{Name: name, Status: StatusWaived})
+	}
+	return states
+}
+
+// failureStatus returns the status of a rule from its failure, nil when the rule passed
+func failureStatus(failure *RuleFailure) RuleStatus {
+	switch {
+	case failure == nil:
+		return StatusPass
+	case failure.Unevaluated:
+		return StatusUnevaluated
+	case failure.Severity == rules.SeverityError:
+		return StatusFail
+	default:
+		return StatusWarn
+	}
+}
+
+// statusChanges lists the rules whose status differs between two checks, current rules first
+func statusChanges(previous, current []RuleState) []RuleChange {
+	before := make(map[string]RuleStatus, len(previous))
+	for _, state := range previous {
+		before[state.Name] = state.Status
+	}
+
+	var changes []RuleChange
+	for _, state := range current {
+		if status, found := before[state.Name]; !found || status != state.Status {
+			changes = append(changes, RuleChange{Name: state.Name, Previous: status, Current: state.Status})
+		}
+		delete(before, state.Name)
+	}
+	for _, state := range previous {
+		if _, removed := before[state.Name]; removed {
+			changes = append(changes, RuleChange{Name: state.Name, Previous: state.Status})
+		}
+	}
+	return changes
+}
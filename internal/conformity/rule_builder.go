@@ -3,39 +3,52 @@ package conformity
 import (
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/rules"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
 // RuleBuilder handles building rules from configuration
-type RuleBuilder struct{}
+type RuleBuilder struct {
+	gitlabClient *gitlab.Client
+	logger       *logger.Logger
+}
 
 // NewRuleBuilder creates a new rule builder
-func NewRuleBuilder() *RuleBuilder {
-	return &RuleBuilder{}
+func NewRuleBuilder(client *gitlab.Client, log *logger.Logger) *RuleBuilder {
+	return &RuleBuilder{
+		gitlabClient: client,
+		logger:       log,
+	}
 }
 
-// BuildRules creates rules based on the provided config
-func (rb *RuleBuilder) BuildRules(rulesConfig config.RulesConfig) []rules.Rule {
-	var rulesList []rules.Rule
-
-	// Conditionally initialize rules based on configuration
-	if rulesConfig.Title.Enabled {
-		rulesList = append(rulesList, rules.NewTitleRule(rulesConfig.Title))
-	}
-	if rulesConfig.Description.Enabled {
-		rulesList = append(rulesList, rules.NewDescriptionRule(rulesConfig.Description))
-	}
-	if rulesConfig.Branch.Enabled {
-		rulesList = append(rulesList, rules.NewBranchRule(rulesConfig.Branch))
-	}
-	if rulesConfig.Commits.Enabled {
-		rulesList = append(rulesList, rules.NewCommitsRule(rulesConfig.Commits))
-	}
-	if rulesConfig.Approvals.Enabled {
-		rulesList = append(rulesList, rules.NewApprovalsRule(rulesConfig.Approvals))
-	}
-	if rulesConfig.Squash.Enabled {
-		rulesList = append(rulesList, rules.NewSquashRule(rulesConfig.Squash))
+// BuildRules creates the registered rules enabled by the provided config, after applying the profiles matching
+// the branches of the merge request. Exempted rules are left out and their names returned as waived, and
+// drafts in reduced mode only keep the draft rules. Rules report their messages from the given catalogue. The
+// effective configuration is returned along with the rules.
+func (rb *RuleBuilder) BuildRules(rulesConfig config.RulesConfig, mr *gitlabapi.MergeRequest, exempted []string, messages *i18n.Catalog) ([]rules.Rule, []string, config.RulesConfig) {
+	effective, applied, err := rulesConfig.ForBranches(mr.SourceBranch, mr.TargetBranch)
+	if err != nil {
+		rb.logger.Warn("Failed to apply rule profiles, using base configuration", "error", err)
+	} else if len(applied) > 0 {
+		rb.logger.Debug("Applied rule profiles", "profiles", applied, "source", mr.SourceBranch, "target", mr.TargetBranch)
 	}
+	rulesConfig = effective
+
+	reduced := mr.Draft && rulesConfig.Draft.Mode == DraftModeReduced
+	var waived []string
+
+	// Initialize the rules enabled by the configuration, see rules.Register
+	deps := rules.Dependencies{GitlabClient: rb.gitlabClient, Logger: rb.logger, Messages: messages}
+	rulesList := rules.BuildFiltered(rulesConfig, deps, func(name, kind string) bool {
+		if rules.Listed(exempted, name, kind) {
+			waived = append(waived, name)
+			return false
+		}
+		return !reduced || rules.Listed(rulesConfig.Draft.Rules, name, kind)
+	})
 
-	return rulesList
+	return rulesList, waived, rulesConfig
 }
@@ -1,27 +1,21 @@
 package rules
 
 import (
-	"fmt"
-
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
 type ApprovalsRule struct {
-	config config.ApprovalsConfig
+	config   config.ApprovalsConfig
+	messages *i18n.Catalog
 }
 
-func NewApprovalsRule(cfg interface{}) *ApprovalsRule {
-	approvalsCfg, ok := cfg.(config.ApprovalsConfig)
-	if !ok {
-		approvalsCfg = config.ApprovalsConfig{
-			MinCount: 1,
-		}
-	}
-	return &ApprovalsRule{config: approvalsCfg}
+func NewApprovalsRule(approvalsCfg config.ApprovalsConfig, messages *i18n.Catalog) *ApprovalsRule {
+	return &ApprovalsRule{config: approvalsCfg, messages: messages}
 }
 
 func (r *ApprovalsRule) Name() string {
@@ -31,25 +25,37 @@ func (r *ApprovalsRule) Name() string {
 func (r *ApprovalsRule) Severity() Severity {
 	return SeverityError
 }
-func (r *ApprovalsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
+
+func (r *ApprovalsRule) DataSources() []DataSource {
+	if r.config.UseCodeowners {
+		return []DataSource{DataSourceApprovals, DataSourceMembers, DataSourceCodeowners}
+	}
+	return []DataSource{DataSourceApprovals}
+}
+
+func (r *ApprovalsRule) Inputs() []Input {
+	return []Input{InputMergeRequest, InputApprovals}
+}
+
+func (r *ApprovalsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
 	ruleResult := &RuleResult{}
 
 	if !r.config.UseCodeowners {
 		if approvals.ApprovalsCount < r.config.MinCount {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Insufficient approvals (need %d, have %d)", r.config.MinCount, approvals.ApprovalsCount))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Wait for required approvals before merging")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("approvals.insufficient", r.config.MinCount, approvals.ApprovalsCount))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("approvals.wait_tip"))
 		}
 	} else {
 		if len(cos) == 0 {
-			ruleResult.Error = append(ruleResult.Error, "CODEOWNERS enabled, but could not process owners.")
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Check .gitlab/CODEOWNERS file for validation errors.")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("approvals.codeowners_unavailable"))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("approvals.codeowners_unavailable_tip"))
 		} else {
 			summary := codeowners.CreateCodeOwnersSummary(cos, approvals, members)
 			if summary.AllPatternsApproved {
 				return &RuleResult{Passed: true}, nil
 			}
 
-			aggregatedError, suggestion := summary.GenerateAggregatedOutput()
+			aggregatedError, suggestion := summary.GenerateAggregatedOutput(r.messages)
 
 			ruleResult.Error = append(ruleResult.Error, aggregatedError)
 			if suggestion != "" {
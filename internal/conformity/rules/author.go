@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// noreplyEmailRegex matches GitLab private commit emails such as `123-jane@users.noreply.gitlab.com`
+var noreplyEmailRegex = regexp.MustCompile(`^(?:\d+-)?([^@]+)@users\.noreply\.`)
+
+type AuthorRule struct {
+	config       config.AuthorConfig
+	gitlabClient *gitlab.Client
+	messages     *i18n.Catalog
+}
+
+func NewAuthorRule(authorCfg config.AuthorConfig, client *gitlab.Client, messages *i18n.Catalog) *AuthorRule {
+	return &AuthorRule{config: authorCfg, gitlabClient: client, messages: messages}
+}
+
+func (r *AuthorRule) Name() string {
+	return "Commit Authors"
+}
+
+func (r *AuthorRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *AuthorRule) DataSources() []DataSource {
+	if r.config.RequireMember {
+		return []DataSource{DataSourceCommits, DataSourceMembers}
+	}
+	return []DataSource{DataSourceCommits}
+}
+
+func (r *AuthorRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	invalidDomains := make(map[string][]*gitlabapi.Commit)
+	var domainOrder []string
+	var unknownAuthorCommits []*gitlabapi.Commit
+	memberEmails := make(map[string]bool)
+
+	for _, commit := range commits {
+		if isBotAuthor(commit, r.config.BotAuthors) {
+			continue
+		}
+
+		emails := []string{commit.AuthorEmail}
+		if r.config.CheckCommitter && !strings.EqualFold(commit.CommitterEmail, commit.AuthorEmail) {
+			emails = append(emails, commit.CommitterEmail)
+		}
+
+		// Email domain check
+		if len(r.config.AllowedDomains) > 0 {
+			for _, email := range emails {
+				domain := emailDomain(email)
+				if r.isAllowedDomain(domain) {
+					continue
+				}
+				if invalidDomains[domain] == nil {
+					domainOrder = append(domainOrder, domain)
+				}
+				invalidDomains[domain] = append(invalidDomains[domain], commit)
+				break
+			}
+		}
+
+		// Project member check
+		if r.config.RequireMember {
+			email := strings.ToLower(commit.AuthorEmail)
+			isMember, known := memberEmails[email]
+			if !known {
+				var err error
+				if isMember, err = r.isMemberEmail(email, members); err != nil {
+					return nil, err
+				}
+				memberEmails[email] = isMember
+			}
+			if !isMember {
+				unknownAuthorCommits = append(unknownAuthorCommits, commit)
+			}
+		}
+	}
+
+	ruleResult := &RuleResult{}
+
+	for _, domain := range domainOrder {
+		commits := invalidDomains[domain]
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("author.disallowed_domain", len(commits), domain)+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("author.disallowed_domain_tip", strings.Join(r.config.AllowedDomains, ", ")))
+	}
+
+	if len(unknownAuthorCommits) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("author.not_member", len(unknownAuthorCommits))+formatCommitList(unknownAuthorCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("author.not_member_tip"))
+	}
+
+	if len(ruleResult.Error) != 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// isAllowedDomain reports whether a domain matches any of the allowed domain globs
+func (r *AuthorRule) isAllowedDomain(domain string) bool {
+	for _, pattern := range r.config.AllowedDomains {
+		if match, _ := doublestar.Match(strings.ToLower(pattern), domain); match {
+			return true
+		}
+	}
+	return false
+}
+
+// emailDomain returns the lowercase domain of an email address
+func emailDomain(email string) string {
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		return strings.ToLower(email[at+1:])
+	}
+	return ""
+}
+
+// isMemberEmail reports whether an email belongs to a project member. Member emails are only
+// listed for administrator tokens, other emails are looked up as public emails of GitLab users.
+func (r *AuthorRule) isMemberEmail(email string, members []*gitlabapi.ProjectMember) (bool, error) {
+	if findMemberByEmail(email, members) != nil {
+		return true, nil
+	}
+	if r.gitlabClient == nil {
+		return false, nil
+	}
+
+	username, err := r.gitlabClient.FindUsernameByEmail(email)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up author %s: %w", email, err)
+	}
+	for _, member := range members {
+		if username != "" && strings.EqualFold(member.Username, username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findMemberByEmail returns the project member using an email, either their account email
+// or their GitLab private commit email
+func findMemberByEmail(email string, members []*gitlabapi.ProjectMember) *gitlabapi.ProjectMember {
+	noreplyUsername := ""
+	if groups := noreplyEmailRegex.FindStringSubmatch(strings.ToLower(email)); groups != nil {
+		noreplyUsername = groups[1]
+	}
+
+	for _, member := range members {
+		if member.Email != "" && strings.EqualFold(member.Email, email) {
+			return member
+		}
+		if noreplyUsername != "" && strings.EqualFold(member.Username, noreplyUsername) {
+			return member
+		}
+	}
+	return nil
+}
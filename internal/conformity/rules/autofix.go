@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// draftPrefixRegex matches the draft markers GitLab recognizes at the start of a title
+var draftPrefixRegex = regexp.MustCompile(`(?i)^\s*(draft:|\[draft\]|\(draft\))\s*`)
+
+// issueTagRegex matches an issue key with its brackets and separators, for removal from a title
+var issueTagRegex = regexp.MustCompile(`\s*[\[(]?\b[A-Za-z][A-Za-z0-9]+-[1-9]\d*\b[\])]?:?\s*`)
+
+// slugRegex matches the runs of characters replaced by `-` in slugs
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// typeAliases maps common words for a change, as found in branch prefixes, to Conventional Commits types
+var typeAliases = map[string]string{
+	"feature":       "feat",
+	"features":      "feat",
+	"bug":           "fix",
+	"bugfix":        "fix",
+	"hotfix":        "fix",
+	"doc":           "docs",
+	"documentation": "docs",
+	"refactoring":   "refactor",
+	"tests":         "test",
+	"performance":   "perf",
+}
+
+// ProposeTitle derives a conforming title from the current title and the source branch: the type
+// is normalized or taken from the branch prefix, invalid scopes are dropped and a missing Jira
+// key is taken from the branch. An empty string is returned when no better title can be derived.
+func ProposeTitle(mr *gitlabapi.MergeRequest, cfg config.TitleConfig) string {
+	title := strings.TrimSpace(mr.Title)
+
+	// Keep the draft marker, GitLab relies on it
+	draft := ""
+	if loc := draftPrefixRegex.FindStringIndex(title); loc != nil {
+		draft = strings.TrimSpace(title[:loc[1]]) + " "
+		title = title[loc[1]:]
+	}
+
+	commitType, scope, breaking, description := "", "", false, title
+	if parsed, err := common.ParseConventionalCommit(title); err == nil {
+		commitType, scope, breaking, description = parsed.Type, parsed.Scope, parsed.Breaking, parsed.Description
+	}
+
+	// Type, from the title or else the branch prefix
+	proposedType := resolveType(commitType, cfg.Conventional.Types)
+	if proposedType == "" {
+		if commitType != "" && resolveType(commitType, nil) == "" {
+			// Not a type at all, such as `Update: ...`, keep it in the description
+			description = title
+		}
+		branchPrefix := strings.SplitN(mr.SourceBranch, "/", 2)[0]
+		proposedType = resolveType(branchPrefix, cfg.Conventional.Types)
+	}
+	if proposedType == "" {
+		return ""
+	}
+
+	// Scope, dropped when not allowed
+	if scope != "" && len(cfg.Conventional.Scopes) > 0 {
+		allowed := false
+		for _, pattern := range cfg.Conventional.Scopes {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(scope) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			scope = ""
+		}
+	}
+
+	// Jira key, moved to the end of the title
+	issue := ""
+	if len(cfg.Jira.Keys) > 0 {
+		if keys := issueKeys(title, cfg.Jira.Keys, false); len(keys) > 0 {
+			issue = keys[0]
+		} else if keys := issueKeys(mr.SourceBranch, cfg.Jira.Keys, true); len(keys) > 0 {
+			issue = keys[0]
+		}
+		if issue != "" {
+			description = issueTagRegex.ReplaceAllString(description, " ")
+		}
+	}
+
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return ""
+	}
+
+	proposal := draft + proposedType
+	if scope != "" {
+		proposal += "(" + scope + ")"
+	}
+	if breaking {
+		proposal += "!"
+	}
+	proposal += ": " + description
+	if issue != "" {
+		proposal += " [" + issue + "]"
+	}
+
+	if proposal == mr.Title {
+		return ""
+	}
+	return proposal
+}
+
+// resolveType returns the allowed type a word stands for, comparing case-insensitively and
+// resolving aliases. Without allowed types, any known type or alias is accepted.
+func resolveType(word string, allowed []string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if alias, ok := typeAliases[word]; ok {
+		word = alias
+	}
+	if len(allowed) == 0 {
+		if common.Contains(defaultTypeOrder, word) {
+			return word
+		}
+		return ""
+	}
+	for _, t := range allowed {
+		if strings.EqualFold(t, word) {
+			return t
+		}
+	}
+	return ""
+}
+
+// slugify turns a text into lowercase words joined by `-`, at most maxLength long when positive
+func slugify(text string, maxLength int) string {
+	slug := strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if maxLength > 0 && len(slug) > maxLength {
+		slug = strings.TrimRight(slug[:maxLength], "-")
+	}
+	return slug
+}
@@ -2,27 +2,36 @@ package rules
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
+// branchPlaceholderRegex matches template placeholders such as {type} or {slug}
+var branchPlaceholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// Patterns of the template placeholders not depending on the configuration
+const (
+	branchSlugPattern   = `[a-z0-9]+(?:-[a-z0-9]+)*`
+	branchNumberPattern = `\d+`
+	branchAnyPattern    = `.+`
+	branchTypePattern   = `[a-z]+`
+	branchJiraPattern   = `(?i:[A-Z][A-Z0-9]+)-[1-9]\d*`
+)
+
 type BranchRule struct {
-	config config.BranchConfig
+	config   config.BranchConfig
+	messages *i18n.Catalog
 }
 
-func NewBranchRule(cfg interface{}) *BranchRule {
-	branchCfg, ok := cfg.(config.BranchConfig)
-	if !ok {
-		branchCfg = config.BranchConfig{
-			AllowedPrefixes: []string{"feature/", "bugfix/", "hotfix/"},
-		}
-	}
-	return &BranchRule{config: branchCfg}
+func NewBranchRule(branchCfg config.BranchConfig, messages *i18n.Catalog) *BranchRule {
+	return &BranchRule{config: branchCfg, messages: messages}
 }
 
 func (r *BranchRule) Name() string {
@@ -33,16 +42,37 @@ func (r *BranchRule) Severity() Severity {
 	return SeverityWarning
 }
 
-func (r *BranchRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
-	ruleResult := &RuleResult{}
+func (r *BranchRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	ruleResult, err := r.validate(mr.SourceBranch, mr.Title)
+	if err != nil {
+		return nil, err
+	}
 
-	branchName := mr.SourceBranch
+	if len(ruleResult.Error) != 0 {
+		var fixes []Fix
+		if proposal := r.proposeBranchName(mr); proposal != "" {
+			fixes = append(fixes, Fix{Field: FixBranch, Value: proposal})
+		}
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+			Fixes:      fixes,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// validate checks a branch name of a merge request with the given title, returning the issues found
+func (r *BranchRule) validate(branchName, title string) (*RuleResult, error) {
+	ruleResult := &RuleResult{}
 
 	// Check forbidden names
 	for _, forbidden := range r.config.ForbiddenNames {
 		if strings.EqualFold(branchName, forbidden) {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Branch name '%s' is not allowed", branchName))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Use a more descriptive branch name")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.forbidden", branchName))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.forbidden_tip"))
 			break
 		}
 	}
@@ -58,18 +88,241 @@ func (r *BranchRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Comm
 		}
 
 		if !hasValidPrefix {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Branch should start with: %s", strings.Join(r.config.AllowedPrefixes, ", ")))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, fmt.Sprintf("Rename branch to start with '%s'", r.config.AllowedPrefixes[0]))
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.invalid_prefix", strings.Join(r.config.AllowedPrefixes, ", ")))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.invalid_prefix_tip", r.config.AllowedPrefixes[0]))
 		}
 	}
 
-	if len(ruleResult.Error) != 0 {
-		return &RuleResult{
-			Passed:     false,
-			Error:      ruleResult.Error,
-			Suggestion: ruleResult.Suggestion,
-		}, nil
+	// Check length
+	if r.config.MaxLength > 0 && len(branchName) > r.config.MaxLength {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.too_long", len(branchName), r.config.MaxLength))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.too_long_tip"))
 	}
 
-	return &RuleResult{Passed: true}, nil
+	// Check case and characters
+	if r.config.Lowercase && branchName != strings.ToLower(branchName) {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.not_lowercase"))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.not_lowercase_tip", strings.ToLower(branchName)))
+	}
+	if r.config.AllowedChars != "" {
+		charRegex, err := regexp.Compile(`^[` + r.config.AllowedChars + `]*$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed_chars %q: %w", r.config.AllowedChars, err)
+		}
+		if !charRegex.MatchString(branchName) {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.invalid_chars", r.config.AllowedChars))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.invalid_chars_tip"))
+		}
+	}
+
+	// Check patterns and templates, the branch must match any of them
+	if len(r.config.Patterns) > 0 || len(r.config.Templates) > 0 {
+		matched, err := r.matchesAnyPattern(branchName)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			expected := append(append([]string{}, r.config.Templates...), r.config.Patterns...)
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.no_pattern_match", strings.Join(expected, "`, `")))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.patternSuggestion())
+		}
+	}
+
+	// Check that the Jira issue of the branch is the one of the title
+	if r.config.MatchTitleJira {
+		// Without configured keys, slugs like `fix-123` may be mistaken for an issue
+		branchIssues := issueKeys(branchName, r.config.JiraKeys, true)
+		titleIssues := issueKeys(title, r.config.JiraKeys, false)
+		if len(branchIssues) > 0 && len(titleIssues) > 0 && !containsAny(branchIssues, titleIssues) {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("branch.jira_mismatch", branchIssues[0], titleIssues[0]))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("branch.jira_mismatch_tip"))
+		}
+	}
+
+	return ruleResult, nil
+}
+
+// proposeBranchName derives a conforming branch name from the title and the current branch,
+// following the first template when any. An empty string is returned when the derived name
+// would not conform either.
+func (r *BranchRule) proposeBranchName(mr *gitlabapi.MergeRequest) string {
+	title := strings.TrimSpace(draftPrefixRegex.ReplaceAllString(mr.Title, ""))
+	currentPrefix, currentRest, hasPrefix := strings.Cut(mr.SourceBranch, "/")
+	if !hasPrefix {
+		currentPrefix, currentRest = "", mr.SourceBranch
+	}
+
+	commitType, description := "", title
+	if parsed, err := common.ParseConventionalCommit(title); err == nil {
+		commitType = resolveType(parsed.Type, r.config.Types)
+		description = parsed.Description
+	}
+	if commitType == "" {
+		commitType = resolveType(currentPrefix, r.config.Types)
+	}
+
+	issue := ""
+	if keys := issueKeys(title, r.config.JiraKeys, false); len(keys) > 0 {
+		issue = keys[0]
+	} else if keys := issueKeys(mr.SourceBranch, r.config.JiraKeys, true); len(keys) > 0 {
+		issue = keys[0]
+	}
+	description = issueTagRegex.ReplaceAllString(description, " ")
+
+	var proposal string
+	if len(r.config.Templates) > 0 {
+		missing := false
+		proposal = branchPlaceholderRegex.ReplaceAllStringFunc(r.config.Templates[0], func(placeholder string) string {
+			var value string
+			switch placeholder {
+			case "{type}":
+				value = commitType
+			case "{jira}":
+				value = issue
+			case "{slug}", "{any}":
+				value = slugify(description, 0)
+			}
+			if value == "" {
+				missing = true
+			}
+			return value
+		})
+		if missing {
+			return ""
+		}
+	} else {
+		prefix := ""
+		if hasPrefix {
+			prefix = currentPrefix + "/"
+		}
+		if len(r.config.AllowedPrefixes) > 0 && !hasAnyPrefix(mr.SourceBranch, r.config.AllowedPrefixes) {
+			prefix = r.config.AllowedPrefixes[0]
+			for _, allowed := range r.config.AllowedPrefixes {
+				if commitType != "" && resolveType(strings.TrimSuffix(allowed, "/"), nil) == resolveType(commitType, nil) {
+					prefix = allowed
+					break
+				}
+			}
+		}
+		proposal = prefix + slugify(currentRest, 0)
+	}
+
+	if r.config.Lowercase {
+		proposal = strings.ToLower(proposal)
+	}
+	if r.config.MaxLength > 0 && len(proposal) > r.config.MaxLength {
+		proposal = strings.TrimRight(proposal[:r.config.MaxLength], "-/")
+	}
+
+	if proposal == mr.SourceBranch {
+		return ""
+	}
+	if result, err := r.validate(proposal, mr.Title); err != nil || len(result.Error) > 0 {
+		return ""
+	}
+	return proposal
+}
+
+// matchesAnyPattern reports whether the branch matches any configured regex or template
+func (r *BranchRule) matchesAnyPattern(branchName string) (bool, error) {
+	for _, pattern := range r.config.Patterns {
+		patternRegex, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+		if patternRegex.MatchString(branchName) {
+			return true, nil
+		}
+	}
+	for _, template := range r.config.Templates {
+		templateRegex, err := r.compileTemplate(template)
+		if err != nil {
+			return false, err
+		}
+		if templateRegex.MatchString(branchName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compileTemplate turns a template like `{type}/{jira}-{slug}` into an anchored regex.
+// Supported placeholders are {type}, {jira}, {slug}, {number} and {any}.
+func (r *BranchRule) compileTemplate(template string) (*regexp.Regexp, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	last := 0
+	for _, loc := range branchPlaceholderRegex.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		last = loc[1]
+
+		switch placeholder := template[loc[2]:loc[3]]; placeholder {
+		case "type":
+			pattern.WriteString(alternation(r.config.Types, branchTypePattern, false))
+		case "jira":
+			if len(r.config.JiraKeys) > 0 {
+				pattern.WriteString(alternation(r.config.JiraKeys, "", true) + `-[1-9]\d*`)
+			} else {
+				pattern.WriteString(branchJiraPattern)
+			}
+		case "slug":
+			pattern.WriteString(branchSlugPattern)
+		case "number":
+			pattern.WriteString(branchNumberPattern)
+		case "any":
+			pattern.WriteString(branchAnyPattern)
+		default:
+			return nil, fmt.Errorf("unknown placeholder {%s} in branch template %q", placeholder, template)
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]) + "$")
+
+	return regexp.Compile(pattern.String())
+}
+
+// patternSuggestion gives an example of a valid branch name
+func (r *BranchRule) patternSuggestion() string {
+	if len(r.config.Templates) == 0 {
+		return r.messages.T("branch.pattern_tip")
+	}
+
+	example := branchPlaceholderRegex.ReplaceAllStringFunc(r.config.Templates[0], func(placeholder string) string {
+		switch placeholder {
+		case "{type}":
+			if len(r.config.Types) > 0 {
+				return r.config.Types[0]
+			}
+			return "feat"
+		case "{jira}":
+			if len(r.config.JiraKeys) > 0 {
+				return r.config.JiraKeys[0] + "-123"
+			}
+			return "ABC-123"
+		case "{number}":
+			return "123"
+		default:
+			return "short-description"
+		}
+	})
+	if r.config.Lowercase {
+		example = strings.ToLower(example)
+	}
+	return r.messages.T("branch.template_tip", r.config.Templates[0], example)
+}
+
+// alternation builds a regex group matching any of the literal values, or the fallback
+// pattern when there are none
+func alternation(values []string, fallback string, ignoreCase bool) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	if ignoreCase {
+		return "(?i:" + strings.Join(quoted, "|") + ")"
+	}
+	return "(?:" + strings.Join(quoted, "|") + ")"
 }
@@ -0,0 +1,108 @@
+package rules
+
+import (
+	"gitlab-mr-conformity-bot/internal/config"
+)
+
+// The built-in rules, registered in their default evaluation order
+func init() {
+	Register("title",
+		func(rc config.RulesConfig) (config.TitleConfig, bool) { return rc.Title, rc.Title.Enabled },
+		func(cfg config.TitleConfig, deps Dependencies) Rule { return NewTitleRule(cfg, deps.Messages) })
+
+	Register("description",
+		func(rc config.RulesConfig) (config.DescriptionConfig, bool) {
+			return rc.Description, rc.Description.Enabled
+		},
+		func(cfg config.DescriptionConfig, deps Dependencies) Rule {
+			return NewDescriptionRule(cfg, deps.Messages)
+		})
+
+	Register("branch",
+		func(rc config.RulesConfig) (config.BranchConfig, bool) {
+			// Template placeholders default to the types and Jira keys of the title
+			cfg := rc.Branch
+			if len(cfg.Types) == 0 {
+				cfg.Types = rc.Title.Conventional.Types
+			}
+			if len(cfg.JiraKeys) == 0 {
+				cfg.JiraKeys = rc.Title.Jira.Keys
+			}
+			return cfg, cfg.Enabled
+		},
+		func(cfg config.BranchConfig, deps Dependencies) Rule { return NewBranchRule(cfg, deps.Messages) })
+
+	Register("commits",
+		func(rc config.RulesConfig) (config.CommitsConfig, bool) { return rc.Commits, rc.Commits.Enabled },
+		func(cfg config.CommitsConfig, deps Dependencies) Rule { return NewCommitsRule(cfg, deps.Messages) })
+
+	Register("approvals",
+		func(rc config.RulesConfig) (config.ApprovalsConfig, bool) { return rc.Approvals, rc.Approvals.Enabled },
+		func(cfg config.ApprovalsConfig, deps Dependencies) Rule { return NewApprovalsRule(cfg, deps.Messages) })
+
+	Register("squash",
+		func(rc config.RulesConfig) (config.SquashConfig, bool) { return rc.Squash, rc.Squash.Enabled },
+		func(cfg config.SquashConfig, deps Dependencies) Rule { return NewSquashRule(cfg, deps.Messages) })
+
+	Register("codeowners_lint",
+		func(rc config.RulesConfig) (config.CodeownersLintConfig, bool) {
+			return rc.CodeownersLint, rc.CodeownersLint.Enabled
+		},
+		func(cfg config.CodeownersLintConfig, deps Dependencies) Rule {
+			return NewCodeownersLintRule(cfg, deps.GitlabClient, deps.Logger, deps.Messages)
+		})
+
+	Register("paths",
+		func(rc config.RulesConfig) (config.PathsConfig, bool) { return rc.Paths, rc.Paths.Enabled },
+		func(cfg config.PathsConfig, deps Dependencies) Rule {
+			return NewPathsRule(cfg, deps.Messages)
+		})
+
+	Register("size",
+		func(rc config.RulesConfig) (config.SizeConfig, bool) { return rc.Size, rc.Size.Enabled },
+		func(cfg config.SizeConfig, deps Dependencies) Rule {
+			return NewSizeRule(cfg, deps.Messages)
+		})
+
+	Register("pipeline",
+		func(rc config.RulesConfig) (config.PipelineConfig, bool) { return rc.Pipeline, rc.Pipeline.Enabled },
+		func(cfg config.PipelineConfig, deps Dependencies) Rule {
+			return NewPipelineRule(cfg, deps.GitlabClient, deps.Messages)
+		})
+
+	Register("signature",
+		func(rc config.RulesConfig) (config.SignatureConfig, bool) { return rc.Signature, rc.Signature.Enabled },
+		func(cfg config.SignatureConfig, deps Dependencies) Rule {
+			return NewSignatureRule(cfg, deps.GitlabClient, deps.Messages)
+		})
+
+	Register("author",
+		func(rc config.RulesConfig) (config.AuthorConfig, bool) { return rc.Author, rc.Author.Enabled },
+		func(cfg config.AuthorConfig, deps Dependencies) Rule {
+			return NewAuthorRule(cfg, deps.GitlabClient, deps.Messages)
+		})
+
+	Register("consistency",
+		func(rc config.RulesConfig) (config.ConsistencyConfig, bool) {
+			cfg := rc.Consistency
+			if len(cfg.JiraKeys) == 0 {
+				cfg.JiraKeys = rc.Title.Jira.Keys
+			}
+			return cfg, cfg.Enabled
+		},
+		func(cfg config.ConsistencyConfig, deps Dependencies) Rule {
+			return NewConsistencyRule(cfg, deps.Messages)
+		})
+
+	RegisterList("custom",
+		func(rc config.RulesConfig) []config.CustomRuleConfig { return rc.Custom },
+		func(cfg config.CustomRuleConfig, deps Dependencies) Rule {
+			return NewCustomRule(cfg, deps.Messages)
+		})
+
+	RegisterList("external",
+		func(rc config.RulesConfig) []config.ExternalRuleConfig { return rc.External },
+		func(cfg config.ExternalRuleConfig, deps Dependencies) Rule {
+			return NewExternalRule(cfg, deps.Logger, deps.Messages)
+		})
+}
@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+type CodeownersLintRule struct {
+	config       config.CodeownersLintConfig
+	gitlabClient *gitlab.Client
+	logger       *logger.Logger
+	messages     *i18n.Catalog
+}
+
+func NewCodeownersLintRule(lintCfg config.CodeownersLintConfig, client *gitlab.Client, log *logger.Logger, messages *i18n.Catalog) *CodeownersLintRule {
+	return &CodeownersLintRule{config: lintCfg, gitlabClient: client, logger: log, messages: messages}
+}
+
+func (r *CodeownersLintRule) Name() string {
+	return "CODEOWNERS Lint"
+}
+
+func (r *CodeownersLintRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *CodeownersLintRule) DataSources() []DataSource {
+	return []DataSource{DataSourceMembers, DataSourceDiffs}
+}
+
+func (r *CodeownersLintRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	// Only lint when the merge request touches the CODEOWNERS file
+	if !common.Contains(common.DiffPaths(diffs), gitlab.CodeownersPath) {
+		return &RuleResult{Passed: true}, nil
+	}
+
+	// Read the proposed version from the source project at the head commit
+	file, err := r.gitlabClient.GetCodeownersFileFromRef(mr.SourceProjectID, mr.SHA)
+	if err != nil {
+		r.logger.Debug("CODEOWNERS file not found on source branch, assuming it was deleted", "error", err)
+		return &RuleResult{Passed: true}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode CODEOWNERS: %w", err)
+	}
+
+	parser := codeowners.NewCodeownersParser(r.logger)
+	parser.AddAccessibleMembers(members)
+
+	parsed, err := parser.Parse(strings.NewReader(string(decoded)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CODEOWNERS: %w", err)
+	}
+
+	issues := codeowners.Lint(parsed, members)
+	if len(issues) == 0 {
+		return &RuleResult{Passed: true}, nil
+	}
+
+	// Aggregate issues by kind
+	grouped := make(map[codeowners.LintIssueKind][]codeowners.LintIssue)
+	for _, issue := range issues {
+		grouped[issue.Kind] = append(grouped[issue.Kind], issue)
+	}
+
+	ruleResult := &RuleResult{}
+	kinds := []struct {
+		kind codeowners.LintIssueKind
+		key  string // Message key, with the suggestion under the key suffixed by `_tip`
+	}{
+		{codeowners.LintSyntaxError, "codeowners_lint.syntax_error"},
+		{codeowners.LintUnknownOwner, "codeowners_lint.unknown_owner"},
+		{codeowners.LintUnreachablePattern, "codeowners_lint.unreachable_pattern"},
+		{codeowners.LintImpossibleApprovals, "codeowners_lint.impossible_approvals"},
+	}
+
+	for _, k := range kinds {
+		found := grouped[k.kind]
+		if len(found) == 0 {
+			continue
+		}
+		errorMsg := r.messages.T(k.key, len(found), gitlab.CodeownersPath)
+		for _, issue := range found {
+			if issue.LineNumber > 0 {
+				errorMsg += "\n  - " + r.messages.T("codeowners.line", issue.LineNumber, issue.Message)
+			} else {
+				errorMsg += fmt.Sprintf("\n  - %s", issue.Message)
+			}
+		}
+		ruleResult.Error = append(ruleResult.Error, errorMsg)
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T(k.key+"_tip"))
+	}
+
+	return &RuleResult{
+		Passed:     false,
+		Error:      ruleResult.Error,
+		Suggestion: ruleResult.Suggestion,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package rules
+
+import (
+	"strings"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Policies applied to special commits
+const (
+	CommitPolicyCheck = "check" // Validate like any other commit
+	CommitPolicySkip  = "skip"  // Ignore the commit
+	CommitPolicyFail  = "fail"  // Report the commit as not allowed
+)
+
+// commitKind classifies commits that may need a dedicated policy
+type commitKind int
+
+const (
+	commitRegular commitKind = iota
+	commitBot
+	commitMerge
+	commitRevert
+	commitFixup
+)
+
+// mergeMessagePrefixes detect merge commits when parent IDs are not returned by the API
+var mergeMessagePrefixes = []string{"Merge branch '", "Merge remote-tracking branch '", "Merge tag '", "Merge commit '"}
+
+// fixupMessagePrefixes are the prefixes git uses for commits meant to be autosquashed
+var fixupMessagePrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// classifyCommit returns the kind of a commit, bot authors taking precedence over the message
+func classifyCommit(commit *gitlabapi.Commit, botAuthors []string) commitKind {
+	switch {
+	case isBotAuthor(commit, botAuthors):
+		return commitBot
+	case len(commit.ParentIDs) > 1 || hasAnyPrefix(commit.Message, mergeMessagePrefixes):
+		return commitMerge
+	case strings.HasPrefix(commit.Message, `Revert "`):
+		return commitRevert
+	case hasAnyPrefix(commit.Message, fixupMessagePrefixes):
+		return commitFixup
+	default:
+		return commitRegular
+	}
+}
+
+// isBotAuthor reports whether the commit author name or email matches any of the glob patterns
+func isBotAuthor(commit *gitlabapi.Commit, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		for _, identity := range []string{commit.AuthorEmail, commit.AuthorName} {
+			if match, _ := doublestar.Match(pattern, strings.ToLower(identity)); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
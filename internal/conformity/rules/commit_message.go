@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// DefaultSquashTemplate is GitLab's default squash commit message template
+const DefaultSquashTemplate = "%{title}"
+
+var templateVariableRegex = regexp.MustCompile(`%\{(\w+)\}`)
+
+// RenderCommitTemplate builds a commit message from a GitLab squash or merge commit template.
+// Variables only known at merge time, such as %{approved_by}, render empty, and lines holding
+// nothing but an empty variable are removed like GitLab does.
+func RenderCommitTemplate(template string, mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit) string {
+	values := map[string]string{
+		"title":         mr.Title,
+		"description":   mr.Description,
+		"source_branch": mr.SourceBranch,
+		"target_branch": mr.TargetBranch,
+		"url":           mr.WebURL,
+	}
+	if mr.References != nil {
+		values["reference"] = mr.References.Full
+	}
+
+	// Commits are listed newest first
+	if len(commits) > 0 {
+		first := commits[len(commits)-1]
+		values["first_commit"] = strings.TrimSpace(first.Message)
+		for i := len(commits) - 1; i >= 0; i-- {
+			message := strings.TrimSpace(commits[i].Message)
+			if strings.Contains(message, "\n") {
+				values["first_multiline_commit"] = message
+				break
+			}
+		}
+		if values["first_multiline_commit"] == "" {
+			values["first_multiline_commit"] = mr.Title
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(template, "\n") {
+		rendered := templateVariableRegex.ReplaceAllStringFunc(line, func(variable string) string {
+			return values[templateVariableRegex.FindStringSubmatch(variable)[1]]
+		})
+		onlyVariables := strings.TrimSpace(templateVariableRegex.ReplaceAllString(line, "")) == ""
+		if onlyVariables && templateVariableRegex.MatchString(line) && strings.TrimSpace(rendered) == "" {
+			continue
+		}
+		lines = append(lines, rendered)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// messageCommit wraps a commit message that will only be created at merge time, so it can be
+// validated and reported like the merge request commits
+func messageCommit(label, message string, mr *gitlabapi.MergeRequest) *gitlabapi.Commit {
+	return &gitlabapi.Commit{
+		ShortID: label,
+		Title:   strings.SplitN(message, "\n", 2)[0],
+		Message: message,
+		WebURL:  mr.WebURL,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 	"regexp"
 	"strings"
 
@@ -12,24 +13,12 @@ import (
 )
 
 type CommitsRule struct {
-	config config.CommitsConfig
+	config   config.CommitsConfig
+	messages *i18n.Catalog
 }
 
-func NewCommitsRule(cfg interface{}) *CommitsRule {
-	commitsCfg, ok := cfg.(config.CommitsConfig)
-	if !ok {
-		commitsCfg = config.CommitsConfig{
-			MaxLength: 72,
-			Conventional: config.ConventionalConfig{
-				Types:  []string{"feat"},
-				Scopes: []string{".*"},
-			},
-			Jira: config.JiraConfig{
-				Keys: []string{""},
-			},
-		}
-	}
-	return &CommitsRule{config: commitsCfg}
+func NewCommitsRule(commitsCfg config.CommitsConfig, messages *i18n.Catalog) *CommitsRule {
+	return &CommitsRule{config: commitsCfg, messages: messages}
 }
 
 func (r *CommitsRule) Name() string {
@@ -40,7 +29,68 @@ func (r *CommitsRule) Severity() Severity {
 	return SeverityWarning
 }
 
-func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
+func (r *CommitsRule) DataSources() []DataSource {
+	return []DataSource{DataSourceCommits}
+}
+
+// disallowedCommitMessages describes special commits rejected by a "fail" policy
+var disallowedCommitMessages = []struct {
+	kind commitKind
+	key  string // Message key, with the suggestion under the key suffixed by `_tip`
+}{
+	{commitFixup, "commits.fixup_not_allowed"},
+	{commitMerge, "commits.merge_not_allowed"},
+	{commitRevert, "commits.revert_not_allowed"},
+	{commitBot, "commits.bot_not_allowed"},
+}
+
+// policy returns the configured policy for a kind of commit, with defaults
+// skipping merge, revert and bot commits and checking fixup commits
+func (r *CommitsRule) policy(kind commitKind) string {
+	var policy string
+	switch kind {
+	case commitMerge:
+		policy = r.config.MergeCommits
+	case commitRevert:
+		policy = r.config.RevertCommits
+	case commitFixup:
+		policy = r.config.FixupCommits
+	case commitBot:
+		policy = r.config.BotCommits
+	default:
+		return CommitPolicyCheck
+	}
+	if policy == "" {
+		if kind == commitFixup {
+			return CommitPolicyCheck
+		}
+		return CommitPolicySkip
+	}
+	return policy
+}
+
+// messagesToCheck returns the commit messages that will end up in the target branch: the squash
+// commit message when squashing in squash-aware mode, otherwise the commits and the merge commit message
+func (r *CommitsRule) messagesToCheck(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit) []*gitlabapi.Commit {
+	if mr.SquashOnMerge {
+		if !r.config.SquashAware {
+			return commits
+		}
+		template := r.config.SquashTemplate
+		if template == "" {
+			template = DefaultSquashTemplate
+		}
+		return []*gitlabapi.Commit{messageCommit("squash commit", RenderCommitTemplate(template, mr, commits), mr)}
+	}
+
+	if r.config.MergeTemplate != "" {
+		merge := messageCommit("merge commit", RenderCommitTemplate(r.config.MergeTemplate, mr, commits), mr)
+		return append(append([]*gitlabapi.Commit{}, commits...), merge)
+	}
+	return commits
+}
+
+func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
 	// Aggregation structures - store commit info instead of just strings
 	var tooLongCommits []*gitlabapi.Commit
 	var invalidFormatCommits []*gitlabapi.Commit
@@ -48,8 +98,23 @@ func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Com
 	invalidScopes := make(map[string][]*gitlabapi.Commit)
 	var missingJiraCommits []*gitlabapi.Commit
 	invalidJiraProjects := make(map[string][]*gitlabapi.Commit)
+	// Body and footer issues, in order of first occurrence
+	var bodyIssues []conventionalIssue
+	bodyIssueCommits := make(map[string][]*gitlabapi.Commit)
+
+	disallowedCommits := make(map[commitKind][]*gitlabapi.Commit)
+
+	for _, commit := range r.messagesToCheck(mr, commits) {
+		// Apply the policy of special commits
+		kind := classifyCommit(commit, r.config.BotAuthors)
+		switch r.policy(kind) {
+		case CommitPolicySkip:
+			continue
+		case CommitPolicyFail:
+			disallowedCommits[kind] = append(disallowedCommits[kind], commit)
+			continue
+		}
 
-	for _, commit := range commits {
 		lines := strings.Split(commit.Message, "\n")
 		firstLine := strings.TrimSpace(lines[0])
 
@@ -59,12 +124,12 @@ func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Com
 		}
 
 		// Conventional Commit Check
-		groups := common.ParseHeader(commit.Message)
-		if len(groups) != 7 {
+		parsed, err := common.ParseConventionalCommit(commit.Message)
+		if err != nil {
 			invalidFormatCommits = append(invalidFormatCommits, commit)
-		} else if len(groups) == 7 {
-			ccType := groups[1]
-			ccScope := groups[3]
+		} else {
+			ccType := parsed.Type
+			ccScope := parsed.Scope
 
 			// Type Validation
 			typeIsValid := false
@@ -98,6 +163,14 @@ func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Com
 					invalidScopes[ccScope] = append(invalidScopes[ccScope], commit)
 				}
 			}
+
+			// Body and footer validation
+			for _, issue := range checkConventionalBody(parsed, r.config.Conventional, r.messages) {
+				if bodyIssueCommits[issue.id()] == nil {
+					bodyIssues = append(bodyIssues, issue)
+				}
+				bodyIssueCommits[issue.id()] = append(bodyIssueCommits[issue.id()], commit)
+			}
 		}
 
 		// Jira Issue Check
@@ -120,72 +193,58 @@ func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Com
 	// Build aggregated results
 	ruleResult := &RuleResult{}
 
+	// Aggregate disallowed special commits
+	for _, disallowed := range disallowedCommitMessages {
+		commits := disallowedCommits[disallowed.kind]
+		if len(commits) == 0 {
+			continue
+		}
+		ruleResult.Error = append(ruleResult.Error, r.messages.T(disallowed.key, len(commits))+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T(disallowed.key+"_tip"))
+	}
+
 	// Aggregate too long commits
 	if len(tooLongCommits) > 0 {
-		errorMsg := fmt.Sprintf("%d commit(s) exceed max length of %d chars:", len(tooLongCommits), r.config.MaxLength)
-		for _, commit := range tooLongCommits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Keep commit messages concise and under the character limit")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.too_long", len(tooLongCommits), r.config.MaxLength)+formatCommitList(tooLongCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.too_long_tip"))
 	}
 
 	// Aggregate invalid format commits
 	if len(invalidFormatCommits) > 0 {
-		errorMsg := fmt.Sprintf("%d commit(s) have invalid Conventional Commit format:", len(invalidFormatCommits))
-		for _, commit := range invalidFormatCommits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Use format: \n> ``` \n> type(scope?): description \n> ```\n> Example: \n`feat(auth): add login retry mechanism`\n\n")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.invalid_format", len(invalidFormatCommits))+formatCommitList(invalidFormatCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.invalid_format_tip"))
 	}
 
 	// Aggregate invalid types
 	for invalidType, commits := range invalidTypes {
-		errorMsg := fmt.Sprintf("%d commit(s) use invalid type '%s':", len(commits), invalidType)
-		for _, commit := range commits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion,
-			fmt.Sprintf("Use one of the allowed types: %s", strings.Join(r.config.Conventional.Types, ", ")))
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.invalid_type", len(commits), invalidType)+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.invalid_type_tip", strings.Join(r.config.Conventional.Types, ", ")))
 	}
 
 	// Aggregate invalid scopes
 	for invalidScope, commits := range invalidScopes {
-		errorMsg := fmt.Sprintf("%d commit(s) use invalid scope '%s':", len(commits), invalidScope)
-		for _, commit := range commits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Use a valid scope or omit it")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.invalid_scope", len(commits), invalidScope)+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.invalid_scope_tip"))
+	}
+
+	// Aggregate body and footer issues
+	for _, issue := range bodyIssues {
+		commits := bodyIssueCommits[issue.id()]
+		args := append([]interface{}{len(commits)}, issue.Args...)
+		ruleResult.Error = append(ruleResult.Error, r.messages.T(issue.Key, args...)+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, issue.Suggestion)
 	}
 
 	// Aggregate missing Jira commits
 	if len(missingJiraCommits) > 0 {
-		errorMsg := fmt.Sprintf("%d commit(s) missing Jira issue tag:", len(missingJiraCommits))
-		for _, commit := range missingJiraCommits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Include a Jira tag like [ABC-123] or ABC-123 \n> **Example**: \n> `fix(token): handle expired JWT refresh logic [SEC-456] `")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.missing_jira", len(missingJiraCommits))+formatCommitList(missingJiraCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.missing_jira_tip"))
 	}
 
 	// Aggregate invalid Jira projects
 	for invalidProject, commits := range invalidJiraProjects {
-		errorMsg := fmt.Sprintf("* %d commit(s) use invalid Jira project '%s':", len(commits), invalidProject)
-		for _, commit := range commits {
-			commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
-			errorMsg += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
-		}
-		ruleResult.Error = append(ruleResult.Error, errorMsg)
-		ruleResult.Suggestion = append(ruleResult.Suggestion,
-			fmt.Sprintf("Use a valid Jira key such as %s", r.config.Jira.Keys[0]))
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("commits.invalid_jira", len(commits), invalidProject)+formatCommitList(commits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("commits.invalid_jira_tip", r.config.Jira.Keys[0]))
 	}
 
 	if len(ruleResult.Error) != 0 {
@@ -198,3 +257,13 @@ func (r *CommitsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Com
 
 	return &RuleResult{Passed: true}, nil
 }
+
+// formatCommitList renders commits as a markdown list linking to each commit
+func formatCommitList(commits []*gitlabapi.Commit) string {
+	list := ""
+	for _, commit := range commits {
+		commitTitle := common.TruncateCommitMessage(strings.Split(commit.Message, "\n")[0], 50)
+		list += fmt.Sprintf("\n  - %s ([%s](%s))", commitTitle, commit.ShortID, commit.WebURL)
+	}
+	return list
+}
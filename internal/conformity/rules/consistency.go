@@ -0,0 +1,204 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// defaultTypeOrder ranks Conventional Commits types from highest to lowest impact
+var defaultTypeOrder = []string{"feat", "fix", "perf", "refactor", "revert", "build", "ci", "docs", "style", "test", "chore"}
+
+type ConsistencyRule struct {
+	config   config.ConsistencyConfig
+	messages *i18n.Catalog
+}
+
+func NewConsistencyRule(consistencyCfg config.ConsistencyConfig, messages *i18n.Catalog) *ConsistencyRule {
+	if len(consistencyCfg.TypeOrder) == 0 {
+		consistencyCfg.TypeOrder = defaultTypeOrder
+	}
+	return &ConsistencyRule{config: consistencyCfg, messages: messages}
+}
+
+func (r *ConsistencyRule) Name() string {
+	return "Consistency"
+}
+
+func (r *ConsistencyRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *ConsistencyRule) DataSources() []DataSource {
+	if r.config.CommitKeysInTitle || r.config.TitleTypeMatchesCommits {
+		return []DataSource{DataSourceCommits}
+	}
+	return nil
+}
+
+func (r *ConsistencyRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	ruleResult := &RuleResult{}
+	var fixes []Fix
+
+	titleKeys := issueKeys(mr.Title, r.config.JiraKeys, false)
+
+	// Title issue in branch, missing title keys are reported by the title rule
+	if r.config.TitleKeyInBranch && len(titleKeys) > 0 {
+		if !containsAny(issueKeys(mr.SourceBranch, r.config.JiraKeys, true), titleKeys) {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("consistency.branch_missing_key", mr.SourceBranch, strings.Join(titleKeys, ", ")))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("consistency.branch_missing_key_tip", titleKeys[0]))
+		}
+	}
+
+	// Title issue in description
+	if r.config.TitleKeyInDescription && len(titleKeys) > 0 {
+		if !containsAny(issueKeys(mr.Description, r.config.JiraKeys, false), titleKeys) {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("consistency.description_missing_key", strings.Join(titleKeys, ", ")))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("consistency.description_missing_key_tip", titleKeys[0]))
+			description := strings.TrimSpace(mr.Description)
+			if description != "" {
+				description += "\n\n"
+			}
+			fixes = append(fixes, Fix{Field: FixDescription, Value: description + r.messages.T("consistency.closes", titleKeys[0])})
+		}
+	}
+
+	// Commits are compared to the title, special commits are ignored
+	var regularCommits []*gitlabapi.Commit
+	for _, commit := range commits {
+		if classifyCommit(commit, r.config.BotAuthors) == commitRegular {
+			regularCommits = append(regularCommits, commit)
+		}
+	}
+
+	// Commit issues among the title issues
+	if r.config.CommitKeysInTitle {
+		var foreignKeys []string
+		var foreignCommits []*gitlabapi.Commit
+		for _, commit := range regularCommits {
+			foreign := false
+			for _, key := range issueKeys(commit.Message, r.config.JiraKeys, false) {
+				if common.Contains(titleKeys, key) {
+					continue
+				}
+				foreign = true
+				if !common.Contains(foreignKeys, key) {
+					foreignKeys = append(foreignKeys, key)
+				}
+			}
+			if foreign {
+				foreignCommits = append(foreignCommits, commit)
+			}
+		}
+
+		if len(foreignCommits) > 0 {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("consistency.foreign_keys", len(foreignCommits), strings.Join(foreignKeys, ", "))+formatCommitList(foreignCommits))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("consistency.foreign_keys_tip"))
+		}
+	}
+
+	// Title type against the highest-impact commit type
+	if r.config.TitleTypeMatchesCommits {
+		if title, err := common.ParseConventionalCommit(mr.Title); err == nil {
+			expectedType, breaking, found := r.highestImpactType(regularCommits)
+			if found && !strings.EqualFold(title.Type, expectedType) {
+				ruleResult.Error = append(ruleResult.Error, r.messages.T("consistency.type_mismatch", title.Type, expectedType))
+				ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("consistency.type_mismatch_tip", expectedType))
+			}
+			if breaking && !title.Breaking {
+				ruleResult.Error = append(ruleResult.Error, r.messages.T("consistency.breaking_mismatch"))
+				ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("consistency.breaking_mismatch_tip"))
+			}
+		}
+	}
+
+	if len(ruleResult.Error) != 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+			Fixes:      fixes,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// highestImpactType returns the Conventional Commits type of the commits ranking first in the
+// type order, types missing from it ranking last, and whether any commit is breaking
+func (r *ConsistencyRule) highestImpactType(commits []*gitlabapi.Commit) (string, bool, bool) {
+	bestType := ""
+	bestRank := -1
+	breaking := false
+
+	for _, commit := range commits {
+		parsed, err := common.ParseConventionalCommit(commit.Message)
+		if err != nil {
+			continue
+		}
+		breaking = breaking || parsed.Breaking || parsed.HasBreakingChangeFooter()
+
+		rank := len(r.config.TypeOrder)
+		for i, commitType := range r.config.TypeOrder {
+			if strings.EqualFold(commitType, parsed.Type) {
+				rank = i
+				break
+			}
+		}
+		if bestRank < 0 || rank < bestRank {
+			bestType, bestRank = parsed.Type, rank
+		}
+	}
+
+	return bestType, breaking, bestRank >= 0
+}
+
+// issueKeys returns the unique uppercase issue keys, such as PROJ-123, referenced in a text.
+// Only the given project keys are matched when any. ignoreCase also accepts lowercase keys,
+// as used in branch names, and is only safe with project keys since slugs like `fix-123` look alike.
+func issueKeys(text string, projectKeys []string, ignoreCase bool) []string {
+	keyPattern := `[A-Z][A-Z0-9]+`
+	if len(projectKeys) > 0 {
+		keyPattern = alternation(projectKeys, "", false)
+	}
+	if ignoreCase {
+		keyPattern = "(?i:" + keyPattern + ")"
+	}
+	issueRegex, err := regexp.Compile(`(` + keyPattern + `)-[1-9]\d*`)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, loc := range issueRegex.FindAllStringIndex(text, -1) {
+		// Keys must not be glued to other letters or digits, like in MYPROJ-1 or PROJ-12a
+		if loc[0] > 0 && isAlphanumeric(text[loc[0]-1]) || loc[1] < len(text) && isAlphanumeric(text[loc[1]]) {
+			continue
+		}
+		key := strings.ToUpper(text[loc[0]:loc[1]])
+		if !common.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// containsAny reports whether any of the values is in the list
+func containsAny(list []string, values []string) bool {
+	for _, value := range values {
+		if common.Contains(list, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+)
+
+// conventionalIssue is a violation of the body and footer options of a Conventional Commits configuration.
+// Its message is formatted with the number of commits first, then its arguments, so identical issues
+// can be aggregated across commits.
+type conventionalIssue struct {
+	Key        string
+	Args       []interface{}
+	Suggestion string
+}
+
+// id identifies identical issues
+func (i conventionalIssue) id() string {
+	return i.Key + fmt.Sprint(i.Args...)
+}
+
+// checkConventionalBody validates the body and footers of a parsed commit message
+func checkConventionalBody(parsed *common.ConventionalCommit, cfg config.ConventionalConfig, messages *i18n.Catalog) []conventionalIssue {
+	var issues []conventionalIssue
+
+	if cfg.RequireBlankLine && !parsed.BlankLineAfterHeader {
+		issues = append(issues, conventionalIssue{
+			Key:        "commits.no_blank_line",
+			Suggestion: messages.T("commits.no_blank_line_tip"),
+		})
+	}
+
+	if cfg.BodyMaxLineLength > 0 {
+		for _, line := range strings.Split(parsed.Body, "\n") {
+			if len(line) > cfg.BodyMaxLineLength {
+				issues = append(issues, conventionalIssue{
+					Key:        "commits.body_too_long",
+					Args:       []interface{}{cfg.BodyMaxLineLength},
+					Suggestion: messages.T("commits.body_too_long_tip", cfg.BodyMaxLineLength),
+				})
+				break
+			}
+		}
+	}
+
+	if cfg.EnforceBreakingChange {
+		hasFooter := parsed.HasBreakingChangeFooter()
+		if parsed.Breaking && !hasFooter {
+			issues = append(issues, conventionalIssue{
+				Key:        "commits.breaking_without_footer",
+				Suggestion: messages.T("commits.breaking_without_footer_tip"),
+			})
+		}
+		if hasFooter && !parsed.Breaking {
+			issues = append(issues, conventionalIssue{
+				Key:        "commits.footer_without_breaking",
+				Suggestion: messages.T("commits.footer_without_breaking_tip"),
+			})
+		}
+	}
+
+	for _, trailer := range cfg.RequiredTrailers {
+		if !parsed.HasFooter(trailer) {
+			issues = append(issues, conventionalIssue{
+				Key:        "commits.missing_trailer",
+				Args:       []interface{}{trailer},
+				Suggestion: messages.T("commits.missing_trailer_tip", trailer),
+			})
+		}
+	}
+
+	if len(cfg.AllowedFooters) > 0 {
+		for _, footer := range parsed.Footers {
+			if !common.IsBreakingChangeToken(footer.Token) && !containsFold(cfg.AllowedFooters, footer.Token) && !containsFold(cfg.RequiredTrailers, footer.Token) {
+				issues = append(issues, conventionalIssue{
+					Key:        "commits.footer_not_allowed",
+					Args:       []interface{}{footer.Token},
+					Suggestion: messages.T("commits.footer_not_allowed_tip", strings.Join(cfg.AllowedFooters, ", ")),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// containsFold reports whether the slice contains the value, ignoring case
+func containsFold(slice []string, value string) bool {
+	for _, elem := range slice {
+		if strings.EqualFold(elem, value) {
+			return true
+		}
+	}
+	return false
+}
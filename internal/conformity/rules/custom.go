@@ -0,0 +1,224 @@
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// CustomRule is a rule declared in the configuration. Its `when` and `condition` are
+// expressions (https://expr-lang.org) evaluated against the variables built by customRuleEnv:
+//
+//	title, description, labels, source_branch, target_branch, draft, squash, author,
+//	approvals (count, approvers), commits (sha, short_sha, title, message, author_name, author_email)
+//	and files, the changed paths, only fetched when referenced.
+type CustomRule struct {
+	config   config.CustomRuleConfig
+	messages *i18n.Catalog
+
+	when       *vm.Program
+	condition  *vm.Program
+	message    *template.Template
+	suggestion *template.Template
+	needsFiles bool
+	// err is returned by Check when the configuration is invalid
+	err error
+}
+
+func NewCustomRule(customCfg config.CustomRuleConfig, messages *i18n.Catalog) *CustomRule {
+	rule := &CustomRule{config: customCfg, messages: messages}
+	rule.err = rule.compile()
+	return rule
+}
+
+func (r *CustomRule) Name() string {
+	if r.config.Name == "" {
+		return "Custom Rule"
+	}
+	return r.config.Name
+}
+
+func (r *CustomRule) Severity() Severity {
+	if strings.EqualFold(r.config.Severity, "warning") {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+func (r *CustomRule) Inputs() []Input {
+	return []Input{InputMergeRequest, InputApprovals}
+}
+
+func (r *CustomRule) DataSources() []DataSource {
+	if r.needsFiles {
+		return []DataSource{DataSourceApprovals, DataSourceCommits, DataSourceDiffs}
+	}
+	return []DataSource{DataSourceApprovals, DataSourceCommits}
+}
+
+func (r *CustomRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var files []string
+	if r.needsFiles {
+		files = common.DiffPaths(diffs)
+	}
+	env := customRuleEnv(mr, commits, approvals, files)
+
+	if r.when != nil {
+		applies, err := expr.Run(r.when, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate when: %w", err)
+		}
+		if !applies.(bool) {
+			return &RuleResult{Passed: true}, nil
+		}
+	}
+
+	passed, err := expr.Run(r.condition, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate condition: %w", err)
+	}
+	if passed.(bool) {
+		return &RuleResult{Passed: true}, nil
+	}
+
+	message, err := renderTemplate(r.message, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render message: %w", err)
+	}
+	ruleResult := &RuleResult{Passed: false, Error: []string{message}}
+
+	if r.suggestion != nil {
+		suggestion, err := renderTemplate(r.suggestion, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render suggestion: %w", err)
+		}
+		ruleResult.Suggestion = []string{suggestion}
+	}
+
+	return ruleResult, nil
+}
+
+// compile checks the expressions and templates against the variables available to them
+func (r *CustomRule) compile() error {
+	if strings.TrimSpace(r.config.Condition) == "" {
+		return errors.New("custom rule has no condition")
+	}
+
+	env := customRuleEnv(&gitlabapi.MergeRequest{}, nil, nil, nil)
+	var err error
+
+	if strings.TrimSpace(r.config.When) != "" {
+		if r.when, err = expr.Compile(r.config.When, expr.Env(env), expr.AsBool()); err != nil {
+			return fmt.Errorf("invalid when expression: %w", err)
+		}
+	}
+	if r.condition, err = expr.Compile(r.config.Condition, expr.Env(env), expr.AsBool()); err != nil {
+		return fmt.Errorf("invalid condition expression: %w", err)
+	}
+
+	message := r.config.Message
+	if message == "" {
+		message = r.messages.T("custom.condition_not_met", r.config.Condition)
+	}
+	if r.message, err = template.New("message").Option("missingkey=zero").Parse(message); err != nil {
+		return fmt.Errorf("invalid message template: %w", err)
+	}
+	if r.config.Suggestion != "" {
+		if r.suggestion, err = template.New("suggestion").Option("missingkey=zero").Parse(r.config.Suggestion); err != nil {
+			return fmt.Errorf("invalid suggestion template: %w", err)
+		}
+	}
+
+	// Changed files need an extra API call, only made when used
+	for _, program := range []*vm.Program{r.when, r.condition} {
+		if program != nil && referencesIdentifier(program.Node(), "files") {
+			r.needsFiles = true
+		}
+	}
+	if strings.Contains(r.config.Message, ".files") || strings.Contains(r.config.Suggestion, ".files") {
+		r.needsFiles = true
+	}
+
+	return nil
+}
+
+// customRuleEnv builds the variables of custom rule expressions and templates
+func customRuleEnv(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, files []string) map[string]interface{} {
+	author := ""
+	if mr.Author != nil {
+		author = mr.Author.Username
+	}
+
+	approvalCount := 0
+	if approvals != nil {
+		approvalCount = approvals.ApprovalsCount
+	}
+
+	commitList := make([]map[string]interface{}, 0, len(commits))
+	for _, commit := range commits {
+		commitList = append(commitList, map[string]interface{}{
+			"sha":          commit.ID,
+			"short_sha":    commit.ShortID,
+			"title":        commit.Title,
+			"message":      commit.Message,
+			"author_name":  commit.AuthorName,
+			"author_email": commit.AuthorEmail,
+		})
+	}
+
+	labels := []string{}
+	labels = append(labels, mr.Labels...)
+	if files == nil {
+		files = []string{}
+	}
+
+	return map[string]interface{}{
+		"title":         mr.Title,
+		"description":   mr.Description,
+		"labels":        labels,
+		"source_branch": mr.SourceBranch,
+		"target_branch": mr.TargetBranch,
+		"draft":         mr.Draft,
+		"squash":        mr.Squash,
+		"author":        author,
+		"approvals": map[string]interface{}{
+			"count":     approvalCount,
+			"approvers": approvals.Approvers(),
+		},
+		"commits": commitList,
+		"files":   files,
+	}
+}
+
+// referencesIdentifier reports whether an expression uses a variable
+func referencesIdentifier(node ast.Node, name string) bool {
+	return ast.Find(node, func(node ast.Node) bool {
+		identifier, ok := node.(*ast.IdentifierNode)
+		return ok && identifier.Value == name
+	}) != nil
+}
+
+func renderTemplate(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
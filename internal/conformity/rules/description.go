@@ -1,29 +1,23 @@
 package rules
 
 import (
-	"fmt"
 	"strings"
 
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
 type DescriptionRule struct {
-	config config.DescriptionConfig
+	config   config.DescriptionConfig
+	messages *i18n.Catalog
 }
 
-func NewDescriptionRule(cfg interface{}) *DescriptionRule {
-	descCfg, ok := cfg.(config.DescriptionConfig)
-	if !ok {
-		descCfg = config.DescriptionConfig{
-			Required:  true,
-			MinLength: 20,
-		}
-	}
-	return &DescriptionRule{config: descCfg}
+func NewDescriptionRule(descCfg config.DescriptionConfig, messages *i18n.Catalog) *DescriptionRule {
+	return &DescriptionRule{config: descCfg, messages: messages}
 }
 
 func (r *DescriptionRule) Name() string {
@@ -34,18 +28,18 @@ func (r *DescriptionRule) Severity() Severity {
 	return SeverityWarning
 }
 
-func (r *DescriptionRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
+func (r *DescriptionRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
 	description := strings.TrimSpace(mr.Description)
 	ruleResult := &RuleResult{}
 
 	if r.config.Required && description == "" {
-		ruleResult.Error = append(ruleResult.Error, "Description is required")
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Add a description explaining the changes in this merge request")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("description.required"))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("description.required_tip"))
 	}
 
 	if description != "" && len(description) < r.config.MinLength {
-		ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Description too short (minimum %d characters)", r.config.MinLength))
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Provide more details about the changes")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("description.too_short", r.config.MinLength))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("description.too_short_tip"))
 	}
 
 	if len(ruleResult.Error) != 0 {
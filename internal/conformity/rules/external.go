@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Failure policies of external rules
+const (
+	FailurePolicyClosed = "closed" // Report the rule as not evaluated
+	FailurePolicyOpen   = "open"   // Let the rule pass
+)
+
+const (
+	defaultExternalTimeout = 10 * time.Second
+	externalRetryDelay     = 500 * time.Millisecond
+	maxExternalResponse    = 1 << 20
+)
+
+// ExternalRuleRequest is the merge request snapshot POSTed as JSON to external rules
+type ExternalRuleRequest struct {
+	Rule         string                  `json:"rule"`
+	MergeRequest *gitlabapi.MergeRequest `json:"merge_request"`
+	Commits      []*gitlabapi.Commit     `json:"commits"`
+	Approvals    ExternalRuleApprovals   `json:"approvals"`
+	Files        []string                `json:"files,omitempty"` // Only sent with include_files
+}
+
+type ExternalRuleApprovals struct {
+	Count     int      `json:"count"`
+	Approvers []string `json:"approvers"`
+}
+
+// ExternalRuleResponse is the RuleResult external rules answer with
+type ExternalRuleResponse struct {
+	Passed      bool     `json:"passed"`
+	Error       []string `json:"error"`
+	Suggestion  []string `json:"suggestion"`
+	WarningOnly bool     `json:"warning_only"`
+}
+
+// ExternalRule delegates the check of a merge request to an HTTP service
+type ExternalRule struct {
+	config     config.ExternalRuleConfig
+	httpClient *http.Client
+	logger     *logger.Logger
+	messages   *i18n.Catalog
+}
+
+func NewExternalRule(externalCfg config.ExternalRuleConfig, log *logger.Logger, messages *i18n.Catalog) *ExternalRule {
+	if externalCfg.Timeout <= 0 {
+		externalCfg.Timeout = defaultExternalTimeout
+	}
+	if externalCfg.FailurePolicy == "" {
+		externalCfg.FailurePolicy = FailurePolicyClosed
+	}
+	return &ExternalRule{
+		config:     externalCfg,
+		httpClient: &http.Client{Timeout: externalCfg.Timeout},
+		logger:     log,
+		messages:   messages,
+	}
+}
+
+func (r *ExternalRule) Name() string {
+	if r.config.Name == "" {
+		return "External Rule"
+	}
+	return r.config.Name
+}
+
+func (r *ExternalRule) Severity() Severity {
+	if strings.EqualFold(r.config.Severity, "warning") {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+func (r *ExternalRule) Inputs() []Input {
+	return []Input{InputMergeRequest, InputApprovals}
+}
+
+func (r *ExternalRule) DataSources() []DataSource {
+	if r.config.IncludeFiles {
+		return []DataSource{DataSourceApprovals, DataSourceCommits, DataSourceDiffs}
+	}
+	return []DataSource{DataSourceApprovals, DataSourceCommits}
+}
+
+func (r *ExternalRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	if r.config.URL == "" {
+		return nil, errors.New("external rule has no url")
+	}
+
+	request := ExternalRuleRequest{
+		Rule:         r.Name(),
+		MergeRequest: mr,
+		Commits:      commits,
+		Approvals:    ExternalRuleApprovals{Approvers: approvals.Approvers()},
+	}
+	if approvals != nil {
+		request.Approvals.Count = approvals.ApprovalsCount
+	}
+	if r.config.IncludeFiles {
+		request.Files = common.DiffPaths(diffs)
+	}
+
+	response, err := r.call(request)
+	if err != nil {
+		if r.config.FailurePolicy == FailurePolicyOpen {
+			r.logger.Warn("External rule failed, passing it as the failure policy is open", "rule", r.Name(), "error", err)
+			return &RuleResult{Passed: true}, nil
+		}
+		return nil, err
+	}
+
+	if response.Passed {
+		return &RuleResult{Passed: true}, nil
+	}
+	if len(response.Error) == 0 {
+		response.Error = []string{r.messages.T("external.failed_without_details")}
+	}
+	return &RuleResult{
+		Passed:      false,
+		Error:       response.Error,
+		Suggestion:  response.Suggestion,
+		WarningOnly: response.WarningOnly,
+	}, nil
+}
+
+// call posts the snapshot, retrying connection errors, 429 and 5xx responses with a growing delay
+func (r *ExternalRule) call(request ExternalRuleRequest) (*ExternalRuleResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= r.config.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(externalRetryDelay * time.Duration(1<<(attempt-1)))
+		}
+
+		response, retryable, err := r.post(body)
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+		r.logger.Debug("External rule call failed", "rule", r.Name(), "attempt", attempt+1, "error", err)
+	}
+
+	return nil, fmt.Errorf("external rule %s: %w", r.config.URL, lastErr)
+}
+
+// post sends one request, reporting whether a failure is worth retrying
+func (r *ExternalRule) post(body []byte) (*ExternalRuleResponse, bool, error) {
+	req, err := http.NewRequest(http.MethodPost, r.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for name, value := range r.config.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, true, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var response ExternalRuleResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxExternalResponse)).Decode(&response); err != nil {
+		return nil, false, fmt.Errorf("invalid response: %w", err)
+	}
+	return &response, false, nil
+}
@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// externalService answers external rule calls with the given statuses in turn, the last one being
+// repeated, and a body for the 200 OK answers
+func externalService(t *testing.T, statuses []int, body string, received func(*http.Request, ExternalRuleRequest)) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1)) - 1
+		var request ExternalRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		if received != nil {
+			received(r, request)
+		}
+		status := statuses[min(call, len(statuses)-1)]
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestExternalRuleRequest(t *testing.T) {
+	t.Setenv("EXTERNAL_RULE_TOKEN", "secret")
+
+	server, _ := externalService(t, []int{http.StatusOK}, `{"passed": true}`, func(r *http.Request, request ExternalRuleRequest) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer secret")
+		}
+		if request.Rule != "Licences" {
+			t.Errorf("rule = %q, want Licences", request.Rule)
+		}
+		if request.MergeRequest == nil || request.MergeRequest.IID != 7 {
+			t.Errorf("merge request not sent: %+v", request.MergeRequest)
+		}
+		if request.Approvals.Count != 1 || !reflect.DeepEqual(request.Approvals.Approvers, []string{"alice"}) {
+			t.Errorf("approvals = %+v, want 1 approval by alice only", request.Approvals)
+		}
+	})
+
+	rule := NewExternalRule(config.ExternalRuleConfig{
+		Name:    "Licences",
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer ${EXTERNAL_RULE_TOKEN}"},
+	}, logger.New(), i18n.Default())
+	approvals := &common.Approvals{
+		ApprovalsCount: 1,
+		ApprovalsInfo: map[int]common.ApprovalInfo{
+			1: {UserID: 1, Username: "alice", Status: "approved"},
+			2: {UserID: 2, Username: "bob", Status: "unapproved"},
+		},
+	}
+
+	result, err := rule.Check(&gitlabapi.MergeRequest{BasicMergeRequest: gitlabapi.BasicMergeRequest{IID: 7}}, nil, approvals, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("Check() passed = false, want true")
+	}
+}
+
+func TestExternalRuleResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		body     string
+		policy   string
+		retries  int
+		want     *RuleResult
+		wantErr  bool
+		calls    int32
+	}{
+		{
+			name:     "failure with details",
+			statuses: []int{http.StatusOK},
+			body:     `{"passed": false, "error": ["GPL-3.0 dependency"], "suggestion": ["Ask legal"], "warning_only": true}`,
+			want:     &RuleResult{Error: []string{"GPL-3.0 dependency"}, Suggestion: []string{"Ask legal"}, WarningOnly: true},
+			calls:    1,
+		},
+		{
+			name:     "failure without details",
+			statuses: []int{http.StatusOK},
+			body:     `{"passed": false}`,
+			want:     &RuleResult{Error: []string{"The external check failed without details"}},
+			calls:    1,
+		},
+		{
+			name:     "server errors are retried",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			body:     `{"passed": true}`,
+			retries:  1,
+			want:     &RuleResult{Passed: true},
+			calls:    2,
+		},
+		{
+			name:     "client errors are not retried",
+			statuses: []int{http.StatusBadRequest},
+			retries:  2,
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "invalid responses fail closed",
+			statuses: []int{http.StatusOK},
+			body:     `not json`,
+			wantErr:  true,
+			calls:    1,
+		},
+		{
+			name:     "open failure policy passes",
+			statuses: []int{http.StatusInternalServerError},
+			policy:   FailurePolicyOpen,
+			want:     &RuleResult{Passed: true},
+			calls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := externalService(t, tt.statuses, tt.body, nil)
+			rule := NewExternalRule(config.ExternalRuleConfig{URL: server.URL, Retries: tt.retries, FailurePolicy: tt.policy}, logger.New(), i18n.Default())
+
+			result, err := rule.Check(&gitlabapi.MergeRequest{}, nil, nil, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("Check() = %+v, want %+v", result, tt.want)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.calls {
+				t.Errorf("calls = %d, want %d", got, tt.calls)
+			}
+		})
+	}
+}
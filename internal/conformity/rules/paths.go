@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// maxListedPaths limits how many offending paths are listed per issue
+const maxListedPaths = 10
+
+type PathsRule struct {
+	config   config.PathsConfig
+	matcher  *codeowners.PatternMatcher
+	messages *i18n.Catalog
+}
+
+func NewPathsRule(pathsCfg config.PathsConfig, messages *i18n.Catalog) *PathsRule {
+	return &PathsRule{config: pathsCfg, matcher: codeowners.NewPatternMatcher(), messages: messages}
+}
+
+func (r *PathsRule) Name() string {
+	return "Changed Paths"
+}
+
+func (r *PathsRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *PathsRule) DataSources() []DataSource {
+	return []DataSource{DataSourceDiffs}
+}
+
+func (r *PathsRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	paths := common.DiffPaths(diffs)
+
+	ruleResult := &RuleResult{}
+
+	// Max files changed
+	if r.config.MaxFiles > 0 && len(paths) > r.config.MaxFiles {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("paths.too_many_files", len(paths), r.config.MaxFiles))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("paths.too_many_files_tip"))
+	}
+
+	// Forbidden paths
+	if forbidden := r.matchingPaths(paths, r.config.Forbidden); len(forbidden) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("paths.forbidden", len(forbidden))+r.formatPathList(forbidden))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("paths.forbidden_tip", strings.Join(r.config.Forbidden, ", ")))
+	}
+
+	// Required labels
+	for _, policy := range r.config.RequireLabels {
+		matched := r.matchingPaths(paths, policy.Paths)
+		if len(matched) == 0 {
+			continue
+		}
+		var missing []string
+		for _, label := range policy.Labels {
+			if !common.Contains(mr.Labels, label) {
+				missing = append(missing, label)
+			}
+		}
+		if len(missing) > 0 {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("paths.missing_labels",
+				strings.Join(policy.Paths, ", "), strings.Join(missing, ", "))+r.formatPathList(matched))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("paths.missing_labels_tip", strings.Join(missing, ", ")))
+		}
+	}
+
+	// Required companion changes
+	for _, policy := range r.config.RequireChanges {
+		matched := r.matchingPaths(paths, policy.Paths)
+		if len(matched) == 0 {
+			continue
+		}
+		if len(r.matchingPaths(paths, policy.With)) == 0 {
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("paths.missing_changes",
+				strings.Join(policy.Paths, ", "), strings.Join(policy.With, ", "))+r.formatPathList(matched))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("paths.missing_changes_tip", strings.Join(policy.With, ", ")))
+		}
+	}
+
+	if len(ruleResult.Error) != 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// matchingPaths returns the paths matched by any of the patterns
+func (r *PathsRule) matchingPaths(paths []string, patterns []string) []string {
+	var matched []string
+	for _, path := range paths {
+		for _, pattern := range patterns {
+			if r.matcher.MatchesPattern(pattern, path) {
+				matched = append(matched, path)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// formatPathList renders paths as a markdown list, truncated to maxListedPaths
+func (r *PathsRule) formatPathList(paths []string) string {
+	list := ""
+	for i, path := range paths {
+		if i == maxListedPaths {
+			list += "\n  - " + r.messages.T("paths.more", len(paths)-maxListedPaths)
+			break
+		}
+		list += fmt.Sprintf("\n  - `%s`", path)
+	}
+	return list
+}
@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// runningPipelineStatuses are the statuses of a pipeline that has not finished yet
+var runningPipelineStatuses = []string{"created", "waiting_for_resource", "preparing", "pending", "running", "scheduled"}
+
+type PipelineRule struct {
+	config       config.PipelineConfig
+	gitlabClient *gitlab.Client
+	messages     *i18n.Catalog
+}
+
+func NewPipelineRule(pipelineCfg config.PipelineConfig, client *gitlab.Client, messages *i18n.Catalog) *PipelineRule {
+	return &PipelineRule{config: pipelineCfg, gitlabClient: client, messages: messages}
+}
+
+func (r *PipelineRule) Name() string {
+	return "Pipeline"
+}
+
+func (r *PipelineRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *PipelineRule) Inputs() []Input {
+	return []Input{InputMergeRequest, InputPipeline}
+}
+
+func (r *PipelineRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	pipeline := mr.HeadPipeline
+
+	// External pipelines only hold commit statuses, such as the one set by this bot
+	if pipeline == nil || pipeline.Source == "external" {
+		return &RuleResult{
+			Passed:     false,
+			Error:      []string{r.messages.T("pipeline.missing")},
+			Suggestion: []string{r.messages.T("pipeline.missing_tip")},
+		}, nil
+	}
+
+	ruleResult := &RuleResult{}
+
+	if r.config.RequireHeadSHA && pipeline.SHA != mr.SHA {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("pipeline.not_head", pipeline.ID, shortSHA(pipeline.SHA), shortSHA(mr.SHA)))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("pipeline.not_head_tip"))
+	}
+
+	switch {
+	case common.Contains(runningPipelineStatuses, pipeline.Status):
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("pipeline.running", pipeline.ID, pipeline.Status))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("pipeline.running_tip"))
+	case pipeline.Status != "success" && pipeline.Status != "failed":
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("pipeline.unfinished", pipeline.ID, pipeline.Status))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("pipeline.unfinished_tip"))
+	default:
+		// The pipeline status also includes external commit statuses, so the outcome is derived from CI jobs
+		jobs, err := r.gitlabClient.ListPipelineJobs(pipeline.ProjectID, pipeline.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs of pipeline #%d: %w", pipeline.ID, err)
+		}
+		r.checkJobs(pipeline, jobs, ruleResult)
+	}
+
+	if len(ruleResult.Error) != 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// checkJobs reports failed jobs and required jobs or stages that did not succeed
+func (r *PipelineRule) checkJobs(pipeline *gitlabapi.Pipeline, jobs []*gitlabapi.Job, ruleResult *RuleResult) {
+	var failed []string
+	for _, job := range jobs {
+		if (job.Status == "failed" && !job.AllowFailure) || job.Status == "canceled" {
+			failed = append(failed, job.Name)
+		}
+	}
+	if len(failed) > 0 {
+		errorMsg := r.messages.T("pipeline.failed_jobs", len(failed), pipeline.ID)
+		for _, name := range failed {
+			errorMsg += fmt.Sprintf("\n  - `%s`", name)
+		}
+		ruleResult.Error = append(ruleResult.Error, errorMsg)
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("pipeline.failed_jobs_tip"))
+	}
+
+	var issues []string
+	for _, pattern := range r.config.RequiredJobs {
+		matched := false
+		for _, job := range jobs {
+			if match, _ := doublestar.Match(pattern, job.Name); match {
+				matched = true
+				if issue := r.requiredJobIssue(job); issue != "" {
+					issues = append(issues, r.messages.T("pipeline.job_issue", job.Name, issue))
+				}
+			}
+		}
+		if !matched {
+			issues = append(issues, r.messages.T("pipeline.job_not_found", pattern))
+		}
+	}
+	for _, stage := range r.config.RequiredStages {
+		matched := false
+		for _, job := range jobs {
+			if job.Stage != stage {
+				continue
+			}
+			matched = true
+			if issue := r.requiredJobIssue(job); issue != "" {
+				issues = append(issues, r.messages.T("pipeline.stage_job_issue", job.Name, stage, issue))
+			}
+		}
+		if !matched {
+			issues = append(issues, r.messages.T("pipeline.stage_not_found", stage))
+		}
+	}
+	if len(issues) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("pipeline.required_jobs", len(issues))+"\n  - "+strings.Join(issues, "\n  - "))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("pipeline.required_jobs_tip"))
+	}
+}
+
+// requiredJobIssue describes why a required job does not count as succeeded
+func (r *PipelineRule) requiredJobIssue(job *gitlabapi.Job) string {
+	switch {
+	case job.Status == "success":
+		return ""
+	case job.Status == "failed" && job.AllowFailure:
+		return r.messages.T("pipeline.allowed_failure")
+	default:
+		return r.messages.T("pipeline.job_status", job.Status)
+	}
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
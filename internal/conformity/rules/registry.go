@@ -0,0 +1,251 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Dependencies are the services rules may use to fetch data beyond the merge request
+type Dependencies struct {
+	GitlabClient *gitlab.Client
+	Logger       *logger.Logger
+	// Messages translates the errors and suggestions reported by rules
+	Messages *i18n.Catalog
+}
+
+// registration describes how to build the rules of a kind
+type registration struct {
+	kind string
+	// configured builds the rules enabled in the section of the kind
+	configured func(rc config.RulesConfig, deps Dependencies) []Rule
+	// instance builds a rule from instance settings applied over the section of the kind
+	instance func(rc config.RulesConfig, settings map[string]interface{}, deps Dependencies) (Rule, error)
+}
+
+// registry holds the rule kinds in registration order, which is the default evaluation order
+var registry []registration
+
+// Register adds a kind of rule configured by a section of the rules configuration.
+// section returns the settings of the kind and whether the rule is enabled.
+func Register[C any](kind string, section func(config.RulesConfig) (C, bool), newRule func(C, Dependencies) Rule) {
+	register(registration{
+		kind: kind,
+		configured: func(rc config.RulesConfig, deps Dependencies) []Rule {
+			if cfg, enabled := section(rc); enabled {
+				return []Rule{newRule(cfg, deps)}
+			}
+			return nil
+		},
+		instance: func(rc config.RulesConfig, settings map[string]interface{}, deps Dependencies) (Rule, error) {
+			cfg, _ := section(rc)
+			if err := config.DecodeOverrides(settings, &cfg); err != nil {
+				return nil, err
+			}
+			return newRule(cfg, deps), nil
+		},
+	})
+}
+
+// RegisterList adds a kind of rule configured by a list, such as custom rules, each entry
+// being a rule. Instances of the kind start from empty settings.
+func RegisterList[C any](kind string, sections func(config.RulesConfig) []C, newRule func(C, Dependencies) Rule) {
+	register(registration{
+		kind: kind,
+		configured: func(rc config.RulesConfig, deps Dependencies) []Rule {
+			var rulesList []Rule
+			for _, cfg := range sections(rc) {
+				rulesList = append(rulesList, newRule(cfg, deps))
+			}
+			return rulesList
+		},
+		instance: func(rc config.RulesConfig, settings map[string]interface{}, deps Dependencies) (Rule, error) {
+			var cfg C
+			if err := config.DecodeOverrides(settings, &cfg); err != nil {
+				return nil, err
+			}
+			return newRule(cfg, deps), nil
+		},
+	})
+}
+
+func register(reg registration) {
+	if _, found := lookup(reg.kind); found {
+		panic(fmt.Sprintf("rule kind %q registered twice", reg.kind))
+	}
+	registry = append(registry, reg)
+}
+
+func lookup(kind string) (registration, bool) {
+	for _, reg := range registry {
+		if reg.kind == kind {
+			return reg, true
+		}
+	}
+	return registration{}, false
+}
+
+// Kinds returns the registered rule kinds in their default evaluation order
+func Kinds() []string {
+	kinds := make([]string, 0, len(registry))
+	for _, reg := range registry {
+		kinds = append(kinds, reg.kind)
+	}
+	return kinds
+}
+
+// builtRule is a rule along with the kind it was built from, used for ordering
+type builtRule struct {
+	kind string
+	rule Rule
+}
+
+// Build creates the rules enabled by the configuration: those enabled in their section, in
+// registration order, then the instances. Rules listed in the configured order, by name or
+// kind, are moved first. Invalid instances and duplicate names are reported by their rule.
+func Build(rc config.RulesConfig, deps Dependencies) []Rule {
+	return rulesOf(build(rc, deps))
+}
+
+// BuildFiltered creates the rules enabled by the configuration like Build, only keeping those
+// for which keep returns true given their name and kind
+func BuildFiltered(rc config.RulesConfig, deps Dependencies, keep func(name, kind string) bool) []Rule {
+	var kept []builtRule
+	for _, b := range build(rc, deps) {
+		if keep(b.rule.Name(), b.kind) {
+			kept = append(kept, b)
+		}
+	}
+	return rulesOf(kept)
+}
+
+// Listed reports whether a rule is in a list of rule names or kinds
+func Listed(list []string, name, kind string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(entry, name) || strings.EqualFold(entry, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func build(rc config.RulesConfig, deps Dependencies) []builtRule {
+	var built []builtRule
+
+	for _, reg := range registry {
+		for _, rule := range reg.configured(rc, deps) {
+			built = append(built, builtRule{kind: reg.kind, rule: rule})
+		}
+	}
+
+	for i, instance := range rc.Instances {
+		built = append(built, builtRule{kind: instance.Kind, rule: buildInstance(rc, instance, i, deps)})
+	}
+
+	// Results are tracked by rule name, so names must be unique
+	names := make(map[string]int)
+	for i, b := range built {
+		name := b.rule.Name()
+		names[name]++
+		if names[name] > 1 {
+			built[i].rule = &invalidRule{name: fmt.Sprintf("%s #%d", name, names[name]), err: fmt.Errorf("another rule is named %q, give each instance a unique name", name)}
+		}
+	}
+
+	sort.SliceStable(built, func(i, j int) bool {
+		return orderIndex(rc.Order, built[i]) < orderIndex(rc.Order, built[j])
+	})
+
+	return built
+}
+
+func rulesOf(built []builtRule) []Rule {
+	rulesList := make([]Rule, 0, len(built))
+	for _, b := range built {
+		rulesList = append(rulesList, b.rule)
+	}
+	return rulesList
+}
+
+// buildInstance creates the rule of an instance, or a rule reporting why it cannot be built
+func buildInstance(rc config.RulesConfig, instance config.RuleInstance, index int, deps Dependencies) Rule {
+	name := instance.Name
+	if name == "" {
+		name = fmt.Sprintf("Rule instance %d (%s)", index+1, instance.Kind)
+	}
+
+	reg, found := lookup(instance.Kind)
+	if !found {
+		return &invalidRule{name: name, err: fmt.Errorf("unknown rule kind %q, available kinds: %s", instance.Kind, strings.Join(Kinds(), ", "))}
+	}
+	rule, err := reg.instance(rc, instance.Settings, deps)
+	if err != nil {
+		return &invalidRule{name: name, err: fmt.Errorf("invalid settings: %w", err)}
+	}
+	if instance.Name == "" {
+		// Unnamed instances must not take the name of the rule section of their kind
+		name = fmt.Sprintf("%s (instance %d)", rule.Name(), index+1)
+	}
+	return &namedRule{Rule: rule, name: name}
+}
+
+// orderIndex returns the position of a rule in the configured order, matching its name or kind
+func orderIndex(order []string, b builtRule) int {
+	for i, entry := range order {
+		if Listed([]string{entry}, b.rule.Name(), b.kind) {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// namedRule renames a rule instance, keeping the optional interfaces of the rule
+type namedRule struct {
+	Rule
+	name string
+}
+
+func (r *namedRule) Name() string {
+	return r.name
+}
+
+func (r *namedRule) DataSources() []DataSource {
+	if dependent, ok := r.Rule.(DataDependent); ok {
+		return dependent.DataSources()
+	}
+	return nil
+}
+
+func (r *namedRule) Inputs() []Input {
+	if dependent, ok := r.Rule.(InputDependent); ok {
+		return dependent.Inputs()
+	}
+	return []Input{InputMergeRequest}
+}
+
+// invalidRule stands for a rule that could not be built, its error being reported when checked
+type invalidRule struct {
+	name string
+	err  error
+}
+
+func (r *invalidRule) Name() string {
+	return r.name
+}
+
+func (r *invalidRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *invalidRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	return nil, r.err
+}
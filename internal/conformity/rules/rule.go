@@ -17,11 +17,93 @@ const (
 type Rule interface {
 	Name() string
 	Severity() Severity
-	Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error)
+	Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error)
 }
 
 type RuleResult struct {
 	Passed     bool
 	Error      []string
 	Suggestion []string
+	// WarningOnly reports the failure as a warning whatever the rule severity
+	WarningOnly bool
+	Fixes       []Fix
+}
+
+// FixField is a merge request field a fix proposes a value for
+type FixField string
+
+const (
+	FixTitle       FixField = "title"
+	FixDescription FixField = "description"
+	FixSquash      FixField = "squash" // Value is "true" or "false"
+	FixBranch      FixField = "branch" // Cannot be applied, the branch has to be renamed by the author
+)
+
+// Fix is a corrected value proposed for a merge request field. Fixes are applied with the
+// `/conform fix <field>` comment command, or right away when Auto is set.
+type Fix struct {
+	Field FixField
+	Value string
+	Auto  bool
+}
+
+// DataSource identifies optional merge request data fetched before rules are checked
+type DataSource string
+
+const (
+	DataSourceApprovals  DataSource = "approvals"
+	DataSourceCommits    DataSource = "commits"
+	DataSourceMembers    DataSource = "project members"
+	DataSourceCodeowners DataSource = "CODEOWNERS"
+	DataSourceDiffs      DataSource = "changes"
+)
+
+// DataDependent is implemented by rules that cannot be evaluated without specific data sources
+type DataDependent interface {
+	DataSources() []DataSource
+}
+
+// RequiresDataSource reports whether any of the rules depends on the data source
+func RequiresDataSource(rulesList []Rule, source DataSource) bool {
+	for _, rule := range rulesList {
+		if dependent, ok := rule.(DataDependent); ok {
+			for _, ruleSource := range dependent.DataSources() {
+				if ruleSource == source {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Input identifies merge request data whose changes can alter rule results
+type Input string
+
+const (
+	InputMergeRequest Input = "merge request" // Title, description, branches, commits, changes and settings
+	InputApprovals    Input = "approvals"
+	InputPipeline     Input = "pipeline"
+)
+
+// InputDependent is implemented by rules depending on more than the merge request content.
+// Rules not implementing it are only re-evaluated when the merge request itself changes.
+type InputDependent interface {
+	Inputs() []Input
+}
+
+// DependsOnAny reports whether the result of a rule may change when any of the inputs change
+func DependsOnAny(rule Rule, inputs []Input) bool {
+	ruleInputs := []Input{InputMergeRequest}
+	if dependent, ok := rule.(InputDependent); ok {
+		ruleInputs = dependent.Inputs()
+	}
+	for _, input := range inputs {
+		for _, ruleInput := range ruleInputs {
+			if input == ruleInput {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/gitlab"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// verifiedSignatureStatuses are the verification statuses GitLab reports for trusted signatures
+var verifiedSignatureStatuses = []string{"verified", "verified_system"}
+
+type SignatureRule struct {
+	config       config.SignatureConfig
+	gitlabClient *gitlab.Client
+	messages     *i18n.Catalog
+}
+
+func NewSignatureRule(signatureCfg config.SignatureConfig, client *gitlab.Client, messages *i18n.Catalog) *SignatureRule {
+	return &SignatureRule{config: signatureCfg, gitlabClient: client, messages: messages}
+}
+
+func (r *SignatureRule) Name() string {
+	return "Commit Signatures"
+}
+
+func (r *SignatureRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *SignatureRule) DataSources() []DataSource {
+	return []DataSource{DataSourceCommits}
+}
+
+func (r *SignatureRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	var unsignedCommits []*gitlabapi.Commit
+	var unverifiedCommits []*gitlabapi.Commit
+	var missingSignOffCommits []*gitlabapi.Commit
+	var mismatchedSignOffCommits []*gitlabapi.Commit
+
+	for _, commit := range commits {
+		kind := classifyCommit(commit, r.config.BotAuthors)
+		if kind == commitBot {
+			continue
+		}
+
+		// Signature verification, commits live in the source project
+		if r.config.RequireVerified {
+			signature, err := r.gitlabClient.GetCommitSignature(mr.SourceProjectID, commit.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify commit %s: %w", commit.ShortID, err)
+			}
+			if signature == nil {
+				unsignedCommits = append(unsignedCommits, commit)
+			} else if !common.Contains(verifiedSignatureStatuses, signature.VerificationStatus) {
+				unverifiedCommits = append(unverifiedCommits, commit)
+			}
+		}
+
+		// Developer Certificate of Origin, merge commits are exempt
+		if r.config.RequireDCO && kind != commitMerge {
+			signedOff, matching := hasAuthorSignOff(commit)
+			if !signedOff {
+				missingSignOffCommits = append(missingSignOffCommits, commit)
+			} else if !matching {
+				mismatchedSignOffCommits = append(mismatchedSignOffCommits, commit)
+			}
+		}
+	}
+
+	ruleResult := &RuleResult{}
+
+	if len(unsignedCommits) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("signature.unsigned", len(unsignedCommits))+formatCommitList(unsignedCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("signature.unsigned_tip"))
+	}
+
+	if len(unverifiedCommits) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("signature.unverified", len(unverifiedCommits))+formatCommitList(unverifiedCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("signature.unverified_tip"))
+	}
+
+	if len(missingSignOffCommits) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("signature.missing_signoff", len(missingSignOffCommits))+formatCommitList(missingSignOffCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("signature.missing_signoff_tip"))
+	}
+
+	if len(mismatchedSignOffCommits) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("signature.mismatched_signoff", len(mismatchedSignOffCommits))+formatCommitList(mismatchedSignOffCommits))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("signature.mismatched_signoff_tip"))
+	}
+
+	if len(ruleResult.Error) != 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// hasAuthorSignOff reports whether a commit has a Signed-off-by trailer and whether one matches its author
+func hasAuthorSignOff(commit *gitlabapi.Commit) (signedOff bool, matching bool) {
+	for _, footer := range common.ParseTrailers(commit.Message) {
+		if !strings.EqualFold(footer.Token, "Signed-off-by") {
+			continue
+		}
+		signedOff = true
+
+		// Identities have the form `Name <email>`
+		value := strings.TrimSpace(footer.Value)
+		start := strings.LastIndex(value, "<")
+		if start < 0 || !strings.HasSuffix(value, ">") {
+			continue
+		}
+		name := strings.TrimSpace(value[:start])
+		email := value[start+1 : len(value)-1]
+		if strings.EqualFold(email, commit.AuthorEmail) && name == strings.TrimSpace(commit.AuthorName) {
+			return true, true
+		}
+	}
+	return signedOff, false
+}
@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/config"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
+	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+type SizeRule struct {
+	config   config.SizeConfig
+	matcher  *codeowners.PatternMatcher
+	messages *i18n.Catalog
+}
+
+// diffStats holds the size of a merge request
+type diffStats struct {
+	additions int
+	deletions int
+	files     int
+	// truncated counts the files whose lines GitLab left out, which cannot be counted
+	truncated int
+}
+
+func NewSizeRule(sizeCfg config.SizeConfig, messages *i18n.Catalog) *SizeRule {
+	return &SizeRule{config: sizeCfg, matcher: codeowners.NewPatternMatcher(), messages: messages}
+}
+
+func (r *SizeRule) Name() string {
+	return "Size"
+}
+
+func (r *SizeRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *SizeRule) DataSources() []DataSource {
+	return []DataSource{DataSourceDiffs}
+}
+
+func (r *SizeRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	// Skip exempt branches
+	for _, pattern := range r.config.ExemptBranches {
+		match, err := doublestar.PathMatch(pattern, mr.SourceBranch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exempt pattern '%s': %v", pattern, err)
+		}
+		if match {
+			return &RuleResult{Passed: true}, nil
+		}
+	}
+
+	stats := r.computeStats(diffs)
+
+	// GitLab stops listing changes past its limits, counting them as "1000+" for instance
+	if count, listed := strings.CutSuffix(mr.ChangesCount, "+"); listed {
+		if files, err := strconv.Atoi(count); err == nil && files > stats.files {
+			stats.files = files
+		}
+	}
+
+	if exceeded := stats.exceeded(r.config.Error, r.messages); len(exceeded) > 0 {
+		return &RuleResult{
+			Passed:     false,
+			Error:      []string{r.messages.T("size.too_large", strings.Join(exceeded, ", "))},
+			Suggestion: []string{r.messages.T("size.too_large_tip")},
+		}, nil
+	}
+
+	ruleResult := &RuleResult{}
+	if exceeded := stats.exceeded(r.config.Warning, r.messages); len(exceeded) > 0 {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("size.large", strings.Join(exceeded, ", ")))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("size.large_tip"))
+	}
+
+	// Lines left out by GitLab may exceed the line limits, which cannot be told
+	if stats.truncated > 0 && (limitsLines(r.config.Error) || limitsLines(r.config.Warning)) {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("size.truncated", stats.truncated))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("size.truncated_tip"))
+	}
+
+	if len(ruleResult.Error) > 0 {
+		return &RuleResult{
+			Passed:      false,
+			Error:       ruleResult.Error,
+			Suggestion:  ruleResult.Suggestion,
+			WarningOnly: true,
+		}, nil
+	}
+
+	return &RuleResult{Passed: true}, nil
+}
+
+// computeStats counts changed files and lines, skipping excluded files. The lines of diffs
+// collapsed or cut by GitLab cannot be counted, such files are counted as truncated.
+func (r *SizeRule) computeStats(diffs []*common.Diff) diffStats {
+	var stats diffStats
+
+	for _, diff := range diffs {
+		if r.isExcluded(diff) {
+			continue
+		}
+		stats.files++
+
+		if diff.Truncated() {
+			stats.truncated++
+			continue
+		}
+
+		for _, line := range strings.Split(diff.Diff, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				stats.additions++
+			case strings.HasPrefix(line, "-"):
+				stats.deletions++
+			}
+		}
+	}
+
+	return stats
+}
+
+// isExcluded reports whether a diff is ignored when measuring the size
+func (r *SizeRule) isExcluded(diff *common.Diff) bool {
+	if r.config.ExcludeGenerated && diff.GeneratedFile {
+		return true
+	}
+	for _, pattern := range r.config.Exclude {
+		if r.matcher.MatchesPattern(pattern, diff.Path()) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitsLines reports whether thresholds limit the number of changed lines
+func limitsLines(limits config.SizeThresholds) bool {
+	return limits.Lines > 0 || limits.Additions > 0 || limits.Deletions > 0
+}
+
+// exceeded lists the limits of the thresholds exceeded by the stats
+func (s diffStats) exceeded(limits config.SizeThresholds, messages *i18n.Catalog) []string {
+	var exceeded []string
+	if limits.Lines > 0 && s.additions+s.deletions > limits.Lines {
+		exceeded = append(exceeded, messages.T("size.lines", s.additions+s.deletions, limits.Lines))
+	}
+	if limits.Additions > 0 && s.additions > limits.Additions {
+		exceeded = append(exceeded, messages.T("size.additions", s.additions, limits.Additions))
+	}
+	if limits.Deletions > 0 && s.deletions > limits.Deletions {
+		exceeded = append(exceeded, messages.T("size.deletions", s.deletions, limits.Deletions))
+	}
+	if limits.Files > 0 && s.files > limits.Files {
+		exceeded = append(exceeded, messages.T("size.files", s.files, limits.Files))
+	}
+	return exceeded
+}
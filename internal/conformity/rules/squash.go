@@ -2,29 +2,32 @@ package rules
 
 import (
 	"fmt"
+	"strconv"
 
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 
 	doublestar "github.com/bmatcuk/doublestar/v4"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
+// Squash policies of branches matched by neither enforce_branches nor disallow_branches
+const (
+	SquashRequire  = "require"
+	SquashDisallow = "disallow"
+	SquashAllow    = "allow"
+)
+
 type SquashRule struct {
-	config config.SquashConfig
+	config   config.SquashConfig
+	messages *i18n.Catalog
 }
 
-func NewSquashRule(cfg interface{}) *SquashRule {
-	squashCfg, ok := cfg.(config.SquashConfig)
-	if !ok {
-		squashCfg = config.SquashConfig{
-			EnforceBranches:  []string{"feature/*", "fix/*"},
-			DisallowBranches: []string{"release/*"},
-		}
-	}
-	return &SquashRule{config: squashCfg}
+func NewSquashRule(squashCfg config.SquashConfig, messages *i18n.Catalog) *SquashRule {
+	return &SquashRule{config: squashCfg, messages: messages}
 }
 
 func (r *SquashRule) Name() string {
@@ -35,10 +38,11 @@ func (r *SquashRule) Severity() Severity {
 	return SeverityError
 }
 
-func (r *SquashRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
+func (r *SquashRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
 	branchName := mr.SourceBranch
 	matched := false
 	ruleResult := &RuleResult{}
+	var squash bool // Squash setting required by the failing policy
 
 	// Check if squash is enforced for matching patterns
 	for _, pattern := range r.config.EnforceBranches {
@@ -51,8 +55,9 @@ func (r *SquashRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Comm
 			if mr.SquashOnMerge {
 				return &RuleResult{Passed: true}, nil
 			}
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Branch '%s' must use squash on merge (matched enforce pattern: %s)", branchName, pattern))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Enable squash on merge")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("squash.required", branchName, pattern))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("squash.enable_tip"))
+			squash = true
 			break
 		}
 	}
@@ -68,26 +73,49 @@ func (r *SquashRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Comm
 			if !mr.SquashOnMerge {
 				return &RuleResult{Passed: true}, nil
 			}
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Branch '%s' must not use squash on merge (matched disallow pattern: %s)", branchName, pattern))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Disable squash on merge")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("squash.disallowed", branchName, pattern))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("squash.disable_tip"))
+			squash = false
 			break
 		}
 	}
 
-	// Default behavior for unmatched branches: require squash
+	// Default behavior for unmatched branches, requiring squash unless configured otherwise
 	if !matched {
-		if mr.SquashOnMerge {
+		switch r.config.Default {
+		case "", SquashRequire:
+			if mr.SquashOnMerge {
+				return &RuleResult{Passed: true}, nil
+			}
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("squash.required_default", branchName))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("squash.enable_tip"))
+			squash = true
+		case SquashDisallow:
+			if !mr.SquashOnMerge {
+				return &RuleResult{Passed: true}, nil
+			}
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("squash.disallowed_default", branchName))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("squash.disable_tip"))
+			squash = false
+		case SquashAllow:
 			return &RuleResult{Passed: true}, nil
+		default:
+			return nil, fmt.Errorf("invalid default '%s', use %s, %s or %s", r.config.Default, SquashRequire, SquashDisallow, SquashAllow)
 		}
-		ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Branch '%s' is not matched by any rule and must squash on merge by default", branchName))
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Enable squash on merge")
 	}
 
 	if len(ruleResult.Error) != 0 {
+		var fixes []Fix
+		// When the merge request already has the setting, the project forces squashing one way
+		// and changing the merge request has no effect
+		if mr.Squash != squash {
+			fixes = append(fixes, Fix{Field: FixSquash, Value: strconv.FormatBool(squash), Auto: r.config.AutoFix})
+		}
 		return &RuleResult{
 			Passed:     false,
 			Error:      ruleResult.Error,
 			Suggestion: ruleResult.Suggestion,
+			Fixes:      fixes,
 		}, nil
 	}
 
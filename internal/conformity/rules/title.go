@@ -1,37 +1,24 @@
 package rules
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
 	"gitlab-mr-conformity-bot/internal/config"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/codeowners"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
+	"gitlab-mr-conformity-bot/internal/i18n"
 
 	gitlabapi "gitlab.com/gitlab-org/api/client-go"
 )
 
 type TitleRule struct {
-	config config.TitleConfig
+	config   config.TitleConfig
+	messages *i18n.Catalog
 }
 
-func NewTitleRule(cfg interface{}) *TitleRule {
-	titleCfg, ok := cfg.(config.TitleConfig)
-	if !ok {
-		titleCfg = config.TitleConfig{
-			MinLength: 10,
-			MaxLength: 100,
-			Conventional: config.ConventionalConfig{
-				Types:  []string{"feat"},
-				Scopes: []string{".*"},
-			},
-			Jira: config.JiraConfig{
-				Keys: []string{""},
-			},
-		}
-	}
-	return &TitleRule{config: titleCfg}
+func NewTitleRule(titleCfg config.TitleConfig, messages *i18n.Catalog) *TitleRule {
+	return &TitleRule{config: titleCfg, messages: messages}
 }
 
 func (r *TitleRule) Name() string {
@@ -42,41 +29,59 @@ func (r *TitleRule) Severity() Severity {
 	return SeverityError
 }
 
-func (r *TitleRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember) (*RuleResult, error) {
-	ruleResult := &RuleResult{}
+func (r *TitleRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commit, approvals *common.Approvals, cos []*codeowners.PatternGroup, members []*gitlabapi.ProjectMember, diffs []*common.Diff) (*RuleResult, error) {
+	ruleResult := r.validate(mr.Title)
+
+	if len(ruleResult.Error) != 0 {
+		var fixes []Fix
+		if proposal := r.proposeTitle(mr); proposal != "" {
+			fixes = append(fixes, Fix{Field: FixTitle, Value: proposal, Auto: r.config.AutoFix})
+		}
+		return &RuleResult{
+			Passed:     false,
+			Error:      ruleResult.Error,
+			Suggestion: ruleResult.Suggestion,
+			Fixes:      fixes,
+		}, nil
+	}
 
-	title := mr.Title
+	return &RuleResult{Passed: true}, nil
+}
+
+// validate checks a title, returning the issues found
+func (r *TitleRule) validate(title string) *RuleResult {
+	ruleResult := &RuleResult{}
 
 	// Length Checks
 	if len(title) < r.config.MinLength {
-		ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Title too short (minimum %d characters)", r.config.MinLength))
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Provide a more descriptive title")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("title.too_short", r.config.MinLength))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.too_short_tip"))
 	}
 
 	if len(title) > r.config.MaxLength {
-		ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Title too long (maximum %d characters)", r.config.MaxLength))
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Shorten the title while keeping it descriptive")
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("title.too_long", r.config.MaxLength))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.too_long_tip"))
 	}
 
 	// Forbidden Words
 	titleLower := strings.ToLower(title)
 	for _, word := range r.config.ForbiddenWords {
 		if strings.Contains(titleLower, strings.ToLower(word)) {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Title contains forbidden word: %s", word))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Remove or replace the forbidden word")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("title.forbidden_word", word))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.forbidden_word_tip"))
 			break
 		}
 	}
 
 	// Conventional Commit Check
-	groups := common.ParseHeader(title)
-	if len(groups) != 7 {
-		ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Invalid Conventional Commit format in title: %q", title))
-		ruleResult.Suggestion = append(ruleResult.Suggestion, "Use format:  \n> ```  \n> type(scope?): description  \n> ```\n> Example:  \n`feat(auth): add login retry mechanism`\n\n")
-	} else if len(groups) == 7 {
+	parsed, err := common.ParseConventionalCommit(title)
+	if err != nil {
+		ruleResult.Error = append(ruleResult.Error, r.messages.T("title.invalid_format", title))
+		ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.invalid_format_tip"))
+	} else {
 
-		ccType := groups[1]
-		ccScope := groups[3]
+		ccType := parsed.Type
+		ccScope := parsed.Scope
 
 		// Type Validation
 		typeIsValid := false
@@ -87,8 +92,8 @@ func (r *TitleRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commi
 			}
 		}
 		if !typeIsValid {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Invalid type %q: allowed types are %v", ccType, r.config.Conventional.Types))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, fmt.Sprintf("Use one of the allowed types: %s", strings.Join(r.config.Conventional.Types, ", ")))
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("title.invalid_type", ccType, r.config.Conventional.Types))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.invalid_type_tip", strings.Join(r.config.Conventional.Types, ", ")))
 		}
 
 		// Scope Validation (optional)
@@ -102,8 +107,8 @@ func (r *TitleRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commi
 				}
 			}
 			if !scopeIsValid {
-				ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Invalid scope %q: allowed scopes are %v", ccScope, r.config.Conventional.Scopes))
-				ruleResult.Suggestion = append(ruleResult.Suggestion, "Use a valid scope or omit it")
+				ruleResult.Error = append(ruleResult.Error, r.messages.T("title.invalid_scope", ccScope, r.config.Conventional.Scopes))
+				ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.invalid_scope_tip"))
 			}
 		}
 	}
@@ -111,26 +116,32 @@ func (r *TitleRule) Check(mr *gitlabapi.MergeRequest, commits []*gitlabapi.Commi
 	// Jira Issue Check
 	if len(r.config.Jira.Keys) > 0 {
 		if !common.JiraRegex.MatchString(title) {
-			ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("No Jira issue tag found in title: %q", title))
-			ruleResult.Suggestion = append(ruleResult.Suggestion, "Include a Jira tag like [ABC-123] or ABC-123  \n> **Example**:  \n> `fix(token): handle expired JWT refresh logic [SEC-456] `")
+			ruleResult.Error = append(ruleResult.Error, r.messages.T("title.missing_jira", title))
+			ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.missing_jira_tip"))
 		} else {
 			submatch := common.JiraRegex.FindStringSubmatch(title)
 			jiraProject := submatch[1]
 
 			if !common.Contains(r.config.Jira.Keys, jiraProject) {
-				ruleResult.Error = append(ruleResult.Error, fmt.Sprintf("Jira project %q is not valid. Allowed: %v", jiraProject, r.config.Jira.Keys))
-				ruleResult.Suggestion = append(ruleResult.Suggestion, fmt.Sprintf("Use a valid Jira key such as %s", r.config.Jira.Keys[0]))
+				ruleResult.Error = append(ruleResult.Error, r.messages.T("title.invalid_jira", jiraProject, r.config.Jira.Keys))
+				ruleResult.Suggestion = append(ruleResult.Suggestion, r.messages.T("title.invalid_jira_tip", r.config.Jira.Keys[0]))
 			}
 		}
 	}
 
-	if len(ruleResult.Error) != 0 {
-		return &RuleResult{
-			Passed:     false,
-			Error:      ruleResult.Error,
-			Suggestion: ruleResult.Suggestion,
-		}, nil
-	}
+	return ruleResult
+}
 
-	return &RuleResult{Passed: true}, nil
+// proposeTitle derives a conforming title, see ProposeTitle. An empty string is returned when the
+// derived title would not conform either, for instance when too long or using a forbidden word.
+// The draft marker kept in the proposal is left out of the validation.
+func (r *TitleRule) proposeTitle(mr *gitlabapi.MergeRequest) string {
+	proposal := ProposeTitle(mr, r.config)
+	if proposal == "" {
+		return ""
+	}
+	if result := r.validate(draftPrefixRegex.ReplaceAllString(proposal, "")); len(result.Error) > 0 {
+		return ""
+	}
+	return proposal
 }
@@ -1,82 +1,175 @@
 package conformity
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strings"
+	"text/template"
 
 	"gitlab-mr-conformity-bot/internal/conformity/rules"
+	"gitlab-mr-conformity-bot/internal/i18n"
+	"gitlab-mr-conformity-bot/pkg/logger"
 )
 
-// SummaryGenerator handles generating summaries for check results
-type SummaryGenerator struct{}
+//go:embed templates/*.tmpl
+var templates embed.FS
 
-// NewSummaryGenerator creates a new summary generator
-func NewSummaryGenerator() *SummaryGenerator {
-	return &SummaryGenerator{}
+// reportFailure is a failure as rendered by the templates, its errors paired with their tips
+type reportFailure struct {
+	RuleFailure
+	Issues []reportIssue
 }
 
-// GenerateSummary creates a formatted summary from rule failures
-func (sg *SummaryGenerator) GenerateSummary(failures []RuleFailure) string {
-	if len(failures) == 0 {
-		return sg.generateSuccessSummary()
-	}
-
-	return sg.generateFailureSummary(failures)
+type reportIssue struct {
+	Number int
+	Error  string
+	Tip    string
 }
 
-// generateSuccessSummary creates a summary for when all checks pass
-func (sg *SummaryGenerator) generateSuccessSummary() string {
-	return "## 🧾 **Merge Request Compliance Report**\n\n✅ **All conformity checks passed!**"
+// SummaryGenerator handles generating summaries for check results
+type SummaryGenerator struct {
+	defaults  *template.Template // Built-in templates, used when custom ones fail
+	templates *template.Template // Built-in templates redefined by those of the server
+	logger    *logger.Logger
 }
 
-// generateFailureSummary creates a summary for when checks fail
-func (sg *SummaryGenerator) generateFailureSummary(failures []RuleFailure) string {
-	summary := fmt.Sprintf("## 🧾 **Merge Request Compliance Report**\n\n### ❌ %d conformity check(s) failed:\n\n---\n\n", len(failures))
+// NewSummaryGenerator creates a new summary generator. The *.tmpl files of templatesDir, when set,
+// redefine the built-in templates.
+func NewSummaryGenerator(templatesDir string, log *logger.Logger) (*SummaryGenerator, error) {
+	defaults, err := template.New("report.md.tmpl").Funcs(template.FuncMap{
+		"t":             i18n.Default().T,
+		"field":         fieldName(i18n.Default()),
+		"join":          strings.Join,
+		"severityEmoji": severityEmoji,
+		"statusEmoji":   statusEmoji,
+		"shortSHA":      shortSHA,
+	}).ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in report templates: %w", err)
+	}
 
-	// Sort failures by severity (higher severity first)
-	sortedFailures := sg.sortFailuresBySeverity(failures)
+	sg := &SummaryGenerator{defaults: defaults, templates: defaults, logger: log}
+	if templatesDir == "" {
+		return sg, nil
+	}
 
-	for _, failure := range sortedFailures {
-		summary += sg.formatFailure(failure)
+	files, err := filepath.Glob(filepath.Join(templatesDir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		if sg.templates, err = template.Must(defaults.Clone()).ParseFiles(files...); err != nil {
+			return nil, fmt.Errorf("failed to parse report templates: %w", err)
+		}
 	}
+	return sg, nil
+}
 
+// GenerateSummary renders the report with the messages of a catalogue. The template definitions of
+// custom, such as those configured by a repository, redefine the templates of the server; the
+// built-in templates are used when they cannot be parsed or rendered.
+func (sg *SummaryGenerator) GenerateSummary(report Report, messages *i18n.Catalog, custom string) string {
+	data := struct {
+		Report
+		Failures []reportFailure
+	}{Report: report, Failures: sg.reportFailures(report.Failures)}
+
+	tmpl := sg.withMessages(sg.templates, messages)
+	if custom != "" {
+		if _, err := tmpl.Parse(custom); err != nil {
+			sg.logger.Warn("Failed to parse repository report template, ignoring it", "error", err)
+			tmpl = sg.withMessages(sg.templates, messages)
+		}
+	}
+
+	summary, err := render(tmpl, data)
+	if err != nil && (custom != "" || sg.templates != sg.defaults) {
+		sg.logger.Warn("Failed to render report, using built-in templates", "error", err)
+		summary, err = render(sg.withMessages(sg.defaults, messages), data)
+	}
+	if err != nil {
+		sg.logger.Error("Failed to render report", "error", err)
+		return messages.T("report.render_failed")
+	}
 	return summary
 }
 
-// sortFailuresBySeverity sorts failures with higher severity first
-func (sg *SummaryGenerator) sortFailuresBySeverity(failures []RuleFailure) []RuleFailure {
+// withMessages returns a copy of templates translating messages with a catalogue
+func (sg *SummaryGenerator) withMessages(base *template.Template, messages *i18n.Catalog) *template.Template {
+	return template.Must(base.Clone()).Funcs(template.FuncMap{"t": messages.T, "field": fieldName(messages)})
+}
+
+func render(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "report", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// reportFailures sorts failures with higher severity first and pairs their errors with tips
+func (sg *SummaryGenerator) reportFailures(failures []RuleFailure) []reportFailure {
 	sortedFailures := make([]RuleFailure, len(failures))
 	copy(sortedFailures, failures)
 
-	sort.Slice(sortedFailures, func(i, j int) bool {
+	sort.SliceStable(sortedFailures, func(i, j int) bool {
 		return sortedFailures[i].Severity > sortedFailures[j].Severity
 	})
 
-	return sortedFailures
-}
-
-// formatFailure formats a single rule failure
-func (sg *SummaryGenerator) formatFailure(failure RuleFailure) string {
-	emoji := sg.getSeverityEmoji(failure.Severity)
-
-	summary := fmt.Sprintf("#### %s **%s**\n\n", emoji, failure.RuleName)
-
-	for count, e := range failure.Error {
-		summary += fmt.Sprintf("📄 **Issue %d**: %s\n", count+1, e)
-		if count < len(failure.Suggestion) {
-			summary += fmt.Sprintf(">💡 **Tip**: %s", failure.Suggestion[count])
+	reported := make([]reportFailure, 0, len(sortedFailures))
+	for _, failure := range sortedFailures {
+		issues := make([]reportIssue, 0, len(failure.Error))
+		for count, e := range failure.Error {
+			issue := reportIssue{Number: count + 1, Error: e}
+			if count < len(failure.Suggestion) {
+				issue.Tip = failure.Suggestion[count]
+			}
+			issues = append(issues, issue)
 		}
-		summary += "\n---\n\n"
+		reported = append(reported, reportFailure{RuleFailure: failure, Issues: issues})
 	}
+	return reported
+}
 
-	summary += "\n---\n\n"
-	return summary
+// fieldName translates the name of a field targeted by fixes
+func fieldName(messages *i18n.Catalog) func(rules.FixField) string {
+	return func(field rules.FixField) string {
+		return messages.T("report.fields." + string(field))
+	}
 }
 
-// getSeverityEmoji returns the appropriate emoji for a given severity
-func (sg *SummaryGenerator) getSeverityEmoji(severity rules.Severity) string {
+// severityEmoji returns the appropriate emoji for a given severity
+func severityEmoji(severity rules.Severity) string {
 	if severity == rules.SeverityError {
 		return "❌"
 	}
 	return "⚠️"
 }
+
+// statusEmoji returns the emoji of a rule status, a dash for rules not enabled
+func statusEmoji(status RuleStatus) string {
+	switch status {
+	case StatusPass:
+		return "✅"
+	case StatusWarn:
+		return "⚠️"
+	case StatusFail:
+		return "❌"
+	case StatusWaived:
+		return "🎫"
+	case StatusUnevaluated:
+		return "❔"
+	}
+	return "➖"
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -2,15 +2,20 @@ package gitlab
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"gitlab-mr-conformity-bot/internal/conformity/helper/common"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
+// CodeownersPath is the repository path of the CODEOWNERS file used by the bot
+const CodeownersPath = ".gitlab/CODEOWNERS"
+
 type Client struct {
 	client *gitlab.Client
 }
@@ -118,8 +123,14 @@ func (c *Client) ListMergeRequestCommits(projectID interface{}, mrID int) ([]*gi
 	return commits, nil
 }
 
+// reportMarker identifies the report discussion whatever the language or template of the report
+const reportMarker = "<!-- mr-conform-report -->"
+
+// legacyReportIdentifier identifies reports posted before the marker was added
+const legacyReportIdentifier = "Merge Request Compliance Report"
+
 func (c *Client) CreateUpdateMergeRequestDiscussion(projectID interface{}, mrID int, note string, passed bool) error {
-	identifier := "Merge Request Compliance Report"
+	note = reportMarker + "\n" + note
 
 	// List discussions
 	discussions, err := c.getAllDiscussions(projectID, mrID)
@@ -132,7 +143,7 @@ func (c *Client) CreateUpdateMergeRequestDiscussion(projectID interface{}, mrID
 			if n.System || n.Body == "" {
 				continue
 			}
-			if strings.Contains(n.Body, identifier) {
+			if strings.Contains(n.Body, reportMarker) || strings.Contains(n.Body, legacyReportIdentifier) {
 				// Update the existing note
 				_, _, err := c.client.Notes.UpdateMergeRequestNote(projectID, mrID, n.ID, &gitlab.UpdateMergeRequestNoteOptions{
 					Body: &note,
@@ -177,6 +188,123 @@ func (c *Client) CreateUpdateMergeRequestDiscussion(projectID interface{}, mrID
 	return nil
 }
 
+// SetMergeRequestReviewers replaces the reviewers of a merge request
+func (c *Client) SetMergeRequestReviewers(projectID interface{}, mrID int, reviewerIDs []int) error {
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(projectID, mrID, &gitlab.UpdateMergeRequestOptions{
+		ReviewerIDs: &reviewerIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set merge request reviewers: %w", err)
+	}
+	return nil
+}
+
+// SetMergeRequestTitle changes the title of a merge request
+func (c *Client) SetMergeRequestTitle(projectID interface{}, mrID int, title string) error {
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(projectID, mrID, &gitlab.UpdateMergeRequestOptions{
+		Title: &title,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set merge request title: %w", err)
+	}
+	return nil
+}
+
+// SetMergeRequestDescription changes the description of a merge request
+func (c *Client) SetMergeRequestDescription(projectID interface{}, mrID int, description string) error {
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(projectID, mrID, &gitlab.UpdateMergeRequestOptions{
+		Description: &description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set merge request description: %w", err)
+	}
+	return nil
+}
+
+// SetMergeRequestSquash changes whether a merge request squashes its commits when merged
+func (c *Client) SetMergeRequestSquash(projectID interface{}, mrID int, squash bool) error {
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(projectID, mrID, &gitlab.UpdateMergeRequestOptions{
+		Squash: &squash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set merge request squash: %w", err)
+	}
+	return nil
+}
+
+// ListOpenMergeRequests lists the open merge requests of a project, optionally filtered by source and target branch
+func (c *Client) ListOpenMergeRequests(projectID interface{}, sourceBranch, targetBranch string) ([]*gitlab.BasicMergeRequest, error) {
+	var allMRs []*gitlab.BasicMergeRequest
+	opt := &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		State:       gitlab.Ptr("opened"),
+	}
+	if sourceBranch != "" {
+		opt.SourceBranch = &sourceBranch
+	}
+	if targetBranch != "" {
+		opt.TargetBranch = &targetBranch
+	}
+
+	for {
+		mrs, resp, err := c.client.MergeRequests.ListProjectMergeRequests(projectID, opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open merge requests: %w", err)
+		}
+
+		allMRs = append(allMRs, mrs...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return allMRs, nil
+}
+
+// IsBot reports whether a user is a bot, such as a project access token or service account
+func (c *Client) IsBot(userID int) (bool, error) {
+	user, _, err := c.client.Users.GetUser(userID, gitlab.GetUsersOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user.Bot, nil
+}
+
+// FindUsernameByEmail returns the username of the user with an email, empty when none is found.
+// Without an administrator token, only users showing the email as their public email are found.
+func (c *Client) FindUsernameByEmail(email string) (string, error) {
+	users, _, err := c.client.Users.ListUsers(&gitlab.ListUsersOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 20},
+		Search:      gitlab.Ptr(email),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search users: %w", err)
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.PublicEmail, email) || strings.EqualFold(user.Email, email) {
+			return user.Username, nil
+		}
+	}
+	return "", nil
+}
+
+// CountOpenReviews returns the number of open merge requests a user is currently reviewing
+func (c *Client) CountOpenReviews(userID int) (int, error) {
+	opt := &gitlab.ListMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 1},
+		State:       gitlab.Ptr("opened"),
+		Scope:       gitlab.Ptr("all"),
+		ReviewerID:  gitlab.ReviewerID(userID),
+	}
+	_, resp, err := c.client.MergeRequests.ListMergeRequests(opt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count open reviews: %w", err)
+	}
+	return resp.TotalItems, nil
+}
+
 func (c *Client) CreateMergeRequestNote(projectID interface{}, mrID int, note string) error {
 	opts := &gitlab.CreateMergeRequestNoteOptions{
 		Body: &note,
@@ -260,12 +388,21 @@ func (c *Client) getAllNotes(projectID interface{}, mrID int) ([]*gitlab.Note, e
 	return allNotes, nil
 }
 
-func (c *Client) GetAllDiffsPaths(projectID interface{}, mrID int) ([]string, error) {
-	var allDiffs []*gitlab.MergeRequestDiff
+// GetAllDiffs lists every file diff of a merge request. The diffs are decoded here as the
+// API client leaves out whether GitLab collapsed them or cut them for being too large.
+func (c *Client) GetAllDiffs(projectID interface{}, mrID int) ([]*common.Diff, error) {
+	path := fmt.Sprintf("projects/%s/merge_requests/%d/diffs", gitlab.PathEscape(fmt.Sprint(projectID)), mrID)
 	opt := &gitlab.ListMergeRequestDiffsOptions{ListOptions: gitlab.ListOptions{PerPage: 20}}
+	var allDiffs []*common.Diff
 
 	for {
-		diffs, resp, err := c.client.MergeRequests.ListMergeRequestDiffs(projectID, mrID, opt)
+		req, err := c.client.NewRequest(http.MethodGet, path, opt, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create diffs request: %w", err)
+		}
+
+		var diffs []*common.Diff
+		resp, err := c.client.Do(req, &diffs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list diffs: %w", err)
 		}
@@ -278,17 +415,53 @@ func (c *Client) GetAllDiffsPaths(projectID interface{}, mrID int) ([]string, er
 		opt.Page = resp.NextPage
 	}
 
-	var allPaths []string
+	return allDiffs, nil
+}
 
-	for _, diff := range allDiffs {
-		if diff.DeletedFile {
-			allPaths = append(allPaths, diff.OldPath)
-		} else {
-			allPaths = append(allPaths, diff.NewPath)
+// ListPipelineJobs lists the latest jobs of a pipeline, excluding retried ones
+func (c *Client) ListPipelineJobs(projectID interface{}, pipelineID int) ([]*gitlab.Job, error) {
+	var allJobs []*gitlab.Job
+	opt := &gitlab.ListJobsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+
+	for {
+		jobs, resp, err := c.client.Jobs.ListPipelineJobs(projectID, pipelineID, opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipeline jobs: %w", err)
+		}
+
+		allJobs = append(allJobs, jobs...)
+
+		if resp.NextPage == 0 {
+			break
 		}
+		opt.Page = resp.NextPage
+	}
+
+	return allJobs, nil
+}
+
+// CommitSignature is the GPG, SSH or X.509 signature of a commit
+type CommitSignature struct {
+	SignatureType      string `json:"signature_type"`
+	VerificationStatus string `json:"verification_status"`
+}
+
+// GetCommitSignature returns the signature of a commit, nil when the commit is not signed
+func (c *Client) GetCommitSignature(projectID interface{}, sha string) (*CommitSignature, error) {
+	path := fmt.Sprintf("projects/%s/repository/commits/%s/signature", gitlab.PathEscape(fmt.Sprint(projectID)), url.PathEscape(sha))
+	req, err := c.client.NewRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signature request: %w", err)
 	}
 
-	return allPaths, nil
+	signature := new(CommitSignature)
+	if _, err := c.client.Do(req, signature); err != nil {
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get commit signature: %w", err)
+	}
+	return signature, nil
 }
 
 func (c *Client) GetCodeownersFile(projectID interface{}) (*gitlab.File, error) {
@@ -297,8 +470,13 @@ func (c *Client) GetCodeownersFile(projectID interface{}) (*gitlab.File, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository info: %w", err)
 	}
-	co, _, err := c.client.RepositoryFiles.GetFile(projectID, ".gitlab/CODEOWNERS", &gitlab.GetFileOptions{
-		Ref: &cP.DefaultBranch,
+	return c.GetCodeownersFileFromRef(projectID, cP.DefaultBranch)
+}
+
+// GetCodeownersFileFromRef fetches the CODEOWNERS file at a specific branch, tag or commit
+func (c *Client) GetCodeownersFileFromRef(projectID interface{}, ref string) (*gitlab.File, error) {
+	co, _, err := c.client.RepositoryFiles.GetFile(projectID, CodeownersPath, &gitlab.GetFileOptions{
+		Ref: &ref,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to CODEOWNERS file: %w", err)
@@ -306,6 +484,8 @@ func (c *Client) GetCodeownersFile(projectID interface{}) (*gitlab.File, error)
 
 	return co, nil
 }
+
+// ListProjectMembers returns the active project members, including those inherited from groups
 func (c *Client) ListProjectMembers(projectID interface{}) ([]*gitlab.ProjectMember, error) {
 	var allMembers []*gitlab.ProjectMember
 	opt := &gitlab.ListProjectMembersOptions{ListOptions: gitlab.ListOptions{PerPage: 20}}
@@ -325,13 +505,33 @@ func (c *Client) ListProjectMembers(projectID interface{}) ([]*gitlab.ProjectMem
 	}
 
 	var activeMembers []*gitlab.ProjectMember
-	now := time.Now()
 
 	for _, member := range allMembers {
-		if member.State == "active" && (member.ExpiresAt == nil || time.Time(*member.ExpiresAt).After(now)) {
+		if isActiveMember(member) {
 			activeMembers = append(activeMembers, member)
 		}
 	}
 
 	return activeMembers, nil
 }
+
+// GetProjectMember returns the membership of a user, including the one inherited from groups,
+// or nil when the user is not an active member of the project
+func (c *Client) GetProjectMember(projectID interface{}, userID int) (*gitlab.ProjectMember, error) {
+	member, _, err := c.client.ProjectMembers.GetInheritedProjectMember(projectID, userID)
+	if err != nil {
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project member: %w", err)
+	}
+	if !isActiveMember(member) {
+		return nil, nil
+	}
+	return member, nil
+}
+
+// isActiveMember reports whether a membership is active and not expired
+func isActiveMember(member *gitlab.ProjectMember) bool {
+	return member.State == "active" && (member.ExpiresAt == nil || time.Time(*member.ExpiresAt).After(time.Now()))
+}
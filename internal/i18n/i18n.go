@@ -0,0 +1,192 @@
+// Package i18n translates the messages of rules and reports. Messages are identified by keys and
+// stored in YAML catalogues, one per language, as fmt formats. Keys missing from a catalogue fall
+// back to the English one.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage is the language of the built-in catalogue every other one falls back to
+const DefaultLanguage = "en"
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// defaultCatalog is the built-in English catalogue
+var defaultCatalog = mustLoadBuiltin(DefaultLanguage)
+
+// Catalog holds the messages of a language
+type Catalog struct {
+	language string
+	messages map[string]string
+	fallback *Catalog
+}
+
+// Default returns the built-in English catalogue
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Language returns the language of the catalogue
+func (c *Catalog) Language() string {
+	if c == nil {
+		return DefaultLanguage
+	}
+	return c.language
+}
+
+// T formats the message of a key with its arguments. A nil catalogue uses the built-in one, and
+// unknown keys are returned as is so missing translations remain visible.
+func (c *Catalog) T(key string, args ...interface{}) string {
+	if c == nil {
+		c = defaultCatalog
+	}
+	for catalog := c; catalog != nil; catalog = catalog.fallback {
+		if format, ok := catalog.messages[key]; ok {
+			if len(args) == 0 {
+				return format
+			}
+			return fmt.Sprintf(format, args...)
+		}
+	}
+	return key
+}
+
+// With returns a catalogue overriding some messages, such as those configured by a repository.
+// Overrides are nested like catalogues.
+func (c *Catalog) With(overrides map[string]interface{}) (*Catalog, error) {
+	if c == nil {
+		c = defaultCatalog
+	}
+	if len(overrides) == 0 {
+		return c, nil
+	}
+	messages := make(map[string]string)
+	if err := flatten("", overrides, messages); err != nil {
+		return c, err
+	}
+	return &Catalog{language: c.language, messages: messages, fallback: c}, nil
+}
+
+// Bundle holds the catalogues available to the server: the built-in ones and those of a directory
+type Bundle struct {
+	catalogs map[string]*Catalog
+}
+
+// NewBundle loads the built-in catalogues, then the `<language>.yaml` files of dir when set. Files
+// of a built-in language override its messages.
+func NewBundle(dir string) (*Bundle, error) {
+	bundle := &Bundle{catalogs: map[string]*Catalog{DefaultLanguage: defaultCatalog}}
+
+	builtin, err := locales.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range builtin {
+		language := strings.TrimSuffix(entry.Name(), ".yaml")
+		if language == DefaultLanguage {
+			continue
+		}
+		data, err := locales.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := bundle.add(language, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if dir == "" {
+		return bundle, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalogue: %w", err)
+		}
+		if err := bundle.add(strings.TrimSuffix(filepath.Base(file), ".yaml"), data); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// Catalog returns the catalogue of a language, the English one when unknown or empty
+func (b *Bundle) Catalog(language string) *Catalog {
+	if b != nil {
+		if catalog, ok := b.catalogs[strings.ToLower(language)]; ok {
+			return catalog
+		}
+	}
+	return defaultCatalog
+}
+
+// add registers the messages of a language over those already known for it
+func (b *Bundle) add(language string, data []byte) error {
+	messages, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s catalogue: %w", language, err)
+	}
+	language = strings.ToLower(language)
+	fallback := defaultCatalog
+	if existing, ok := b.catalogs[language]; ok {
+		fallback = existing
+	}
+	b.catalogs[language] = &Catalog{language: language, messages: messages, fallback: fallback}
+	return nil
+}
+
+func mustLoadBuiltin(language string) *Catalog {
+	data, err := locales.ReadFile("locales/" + language + ".yaml")
+	if err != nil {
+		panic(err)
+	}
+	messages, err := parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in %s catalogue: %v", language, err))
+	}
+	return &Catalog{language: language, messages: messages}
+}
+
+// parse reads a catalogue, nested keys being joined with dots
+func parse(data []byte) (map[string]string, error) {
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	messages := make(map[string]string)
+	if err := flatten("", tree, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func flatten(prefix string, tree map[string]interface{}, messages map[string]string) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case string:
+			messages[key] = value
+		case map[string]interface{}:
+			if err := flatten(key, value, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %s is not text", key)
+		}
+	}
+	return nil
+}
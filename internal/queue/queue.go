@@ -22,9 +22,11 @@ type WebhookJob struct {
 	MergeRequestIID string //`json:"merge_request_iid"`
 	WebhookType     string //`json:"webhook_type"`
 	Payload         *gitlabapi.MergeEvent
-	CreatedAt       int64 //`json:"created_at"`
-	Attempts        int   //`json:"attempts"`
-	MaxAttempts     int   //`json:"max_attempts"`
+	Inputs          []string                     // Rule inputs changed by the event, empty to check every rule
+	Comment         *gitlabapi.MergeCommentEvent // Comment whose commands to run instead of a check
+	CreatedAt       int64                        //`json:"created_at"`
+	Attempts        int                          //`json:"attempts"`
+	MaxAttempts     int                          //`json:"max_attempts"`
 }
 
 // JobProcessor defines the interface for processing webhook jobs
@@ -105,18 +107,34 @@ func NewQueueManager(config *Config, log *logger.Logger) *QueueManager {
 }
 
 // EnqueueWebhook adds a webhook job to the queue for a specific MR
-func (qm *QueueManager) EnqueueWebhook(c context.Context, projectID, mergeRequestIID, webhookType string, payload *gitlabapi.MergeEvent) (string, error) {
-	jobID := uuid.New().String()
-	job := &WebhookJob{
-		ID:              jobID,
+func (qm *QueueManager) EnqueueWebhook(c context.Context, projectID, mergeRequestIID, webhookType string, payload *gitlabapi.MergeEvent, inputs []string) (string, error) {
+	return qm.enqueue(c, &WebhookJob{
 		ProjectID:       projectID,
 		MergeRequestIID: mergeRequestIID,
 		WebhookType:     webhookType,
 		Payload:         payload,
-		CreatedAt:       time.Now().Unix(),
-		Attempts:        0,
-		MaxAttempts:     qm.maxRetries,
-	}
+		Inputs:          inputs,
+	})
+}
+
+// EnqueueCommand adds a job running the commands of a comment to the queue of its MR, so they
+// are run in turn with the checks of the MR
+func (qm *QueueManager) EnqueueCommand(c context.Context, projectID, mergeRequestIID string, comment *gitlabapi.MergeCommentEvent) (string, error) {
+	return qm.enqueue(c, &WebhookJob{
+		ProjectID:       projectID,
+		MergeRequestIID: mergeRequestIID,
+		WebhookType:     string(gitlabapi.EventTypeNote),
+		Comment:         comment,
+	})
+}
+
+// enqueue adds a job to the queue of its MR
+func (qm *QueueManager) enqueue(c context.Context, job *WebhookJob) (string, error) {
+	jobID := uuid.New().String()
+	job.ID = jobID
+	job.CreatedAt = time.Now().Unix()
+	job.MaxAttempts = qm.maxRetries
+	projectID, mergeRequestIID := job.ProjectID, job.MergeRequestIID
 
 	jobData, err := json.Marshal(job)
 	if err != nil {
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"regexp"
+	"strings"
+
+	"gitlab-mr-conformity-bot/internal/conformity/rules"
+	"gitlab-mr-conformity-bot/internal/i18n"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// commandRegex matches a `/conform <command> [argument]` line of a comment
+var commandRegex = regexp.MustCompile(`(?m)^/conform\s+(\w+)(?:\s+(\w+))?\s*$`)
+
+// commandNote returns the merge request comment of an event when it holds `/conform` commands
+func commandNote(event interface{}) (*gitlabapi.MergeCommentEvent, bool) {
+	note, ok := event.(*gitlabapi.MergeCommentEvent)
+	if !ok || note.ObjectAttributes.System || note.ObjectAttributes.NoteableType != "MergeRequest" {
+		return nil, false
+	}
+	return note, commandRegex.MatchString(note.ObjectAttributes.Note)
+}
+
+// handleCommands runs the `/conform` commands of a merge request comment, replying with their
+// outcome. Events other than merge request comments are ignored.
+func (s *Server) handleCommands(event interface{}) {
+	if note, ok := commandNote(event); ok {
+		s.runCommands(note)
+	}
+}
+
+// runCommands runs the `/conform` commands of a merge request comment, replying with their outcome
+func (s *Server) runCommands(note *gitlabapi.MergeCommentEvent) {
+	projectID, mrID := note.ProjectID, note.MergeRequest.IID
+	s.logger.Info("Processing merge request commands", "projectId", projectID, "mrId", mrID, "user", note.User.Username)
+
+	messages := s.checker.Messages(projectID)
+
+	var replies []string
+	if allowed, err := s.canRunCommands(projectID, note.User.ID, note.MergeRequest.AuthorID); err != nil {
+		s.logger.Error("Failed to check command permissions", "projectId", projectID, "mrId", mrID, "error", err)
+		return
+	} else if !allowed {
+		replies = append(replies, "❌ "+messages.T("commands.not_allowed", note.User.Username))
+	} else {
+		for _, match := range commandRegex.FindAllStringSubmatch(note.ObjectAttributes.Note, -1) {
+			replies = append(replies, s.runCommand(projectID, mrID, match[1], match[2], messages))
+		}
+	}
+
+	if err := s.gitlabClient.CreateMergeRequestNote(projectID, mrID, strings.Join(replies, "\n\n")); err != nil {
+		s.logger.Error("Failed to reply to commands", "projectId", projectID, "mrId", mrID, "error", err)
+	}
+}
+
+// canRunCommands reports whether a user is the merge request author or at least a developer
+func (s *Server) canRunCommands(projectID interface{}, userID, authorID int) (bool, error) {
+	if userID == authorID {
+		return true, nil
+	}
+
+	// Group members reach the project through inherited memberships
+	member, err := s.gitlabClient.GetProjectMember(projectID, userID)
+	if err != nil || member == nil {
+		return false, err
+	}
+	return member.AccessLevel >= gitlabapi.DeveloperPermissions, nil
+}
+
+// runCommand runs a command and returns the reply describing its outcome
+func (s *Server) runCommand(projectID interface{}, mrID int, command, argument string, messages *i18n.Catalog) string {
+	if command != "fix" {
+		return "❔ " + messages.T("commands.unknown", command) + " " + messages.T("commands.usage")
+	}
+
+	field := rules.FixField(argument)
+	if field != rules.FixTitle && field != rules.FixDescription && field != rules.FixSquash {
+		return "❔ " + messages.T("commands.cannot_fix", argument) + " " + messages.T("commands.usage")
+	}
+	fieldName := messages.T("report.fields." + string(field))
+
+	// Check again so the fix matches the current merge request
+	result, err := s.checkMergeRequest(projectID, mrID, nil)
+	if err != nil {
+		s.logger.Error("Failed to check merge request", "projectId", projectID, "mrId", mrID, "error", err)
+		return "❌ " + messages.T("commands.check_failed", fieldName)
+	}
+
+	for _, failure := range result.Failures {
+		for _, fix := range failure.Fixes {
+			if fix.Field != field {
+				continue
+			}
+			if err := s.checker.ApplyFix(projectID, mrID, fix); err != nil {
+				s.logger.Error("Failed to apply fix", "projectId", projectID, "mrId", mrID, "field", field, "error", err)
+				return "❌ " + messages.T("commands.fix_failed", fieldName, err)
+			}
+			return "🔧 " + messages.T("commands.fixed", fieldName, failure.RuleName)
+		}
+	}
+
+	return "✅ " + messages.T("commands.no_fix", fieldName)
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"fmt"
+	"gitlab-mr-conformity-bot/internal/conformity"
 	"io"
 	"net/http"
 	"strconv"
@@ -25,7 +27,7 @@ func (s *Server) handleHealth(c *gin.Context) {
 func (s *Server) handleWebhookNoQueue(c *gin.Context) {
 	wh := Webhook{
 		Secret:         s.config.GitLab.SecretToken,
-		EventsToAccept: []gitlabapi.EventType{gitlabapi.EventTypeMergeRequest, gitlabapi.EventTypeNote},
+		EventsToAccept: []gitlabapi.EventType{gitlabapi.EventTypeMergeRequest, gitlabapi.EventTypeNote, gitlabapi.EventTypePipeline, gitlabapi.EventTypePush},
 	}
 
 	// If we have a secret set, we should check if the request matches it.
@@ -67,46 +69,115 @@ func (s *Server) handleWebhookNoQueue(c *gin.Context) {
 		return
 	}
 
-	switch parsedEvent := parsedEvent.(type) {
-	case *gitlabapi.MergeEvent:
+	if event, ok := parsedEvent.(*gitlabapi.MergeEvent); ok {
 		s.logger.Info("Processing merge request event",
-			"project_id", parsedEvent.Project.ID,
-			"mr_id", parsedEvent.ObjectAttributes.IID,
-			"action", parsedEvent.ObjectAttributes.Action)
+			"project_id", event.Project.ID,
+			"mr_id", event.ObjectAttributes.IID,
+			"action", event.ObjectAttributes.Action)
+	}
+
+	// Run the commands of merge request comments
+	s.handleCommands(parsedEvent)
+
+	// Map the event to the merge requests it affects
+	targets, err := s.recheckTargets(parsedEvent)
+	if err != nil {
+		s.logger.Error("Failed to find merge requests affected by event", "event", eventType, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find affected merge requests"})
+		return
+	}
+
+	// Pushes to a target branch may affect many merge requests, check them in the background
+	if _, isPush := parsedEvent.(*gitlabapi.PushEvent); isPush && len(targets) > 0 {
+		go s.checkAndReportAll(targets)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":        "Processing in background",
+			"merge_requests": len(targets),
+		})
+		return
+	}
 
-		// Check merge request conformity
-		result, err := s.checker.CheckMergeRequest(parsedEvent.Project.ID, parsedEvent.ObjectAttributes.IID)
+	var results []gin.H
+	for _, target := range targets {
+		result, err := s.checkAndReport(target)
 		if err != nil {
-			s.logger.Error("Failed to check merge request",
-				"project_id", parsedEvent.Project.ID,
-				"mr_id", parsedEvent.ObjectAttributes.IID,
-				"error", err)
-			c.JSON(http.StatusOK, gin.H{"error": "Check failed"})
-			return
+			results = append(results, gin.H{
+				"project_id": target.ProjectID,
+				"mr_id":      target.MergeRequestIID,
+				"error":      err.Error(),
+			})
+			continue
 		}
-
-		// Post discussion with results
-		if err := s.gitlabClient.CreateUpdateMergeRequestDiscussion(parsedEvent.Project.ID, parsedEvent.ObjectAttributes.IID, result.Summary, result.Passed); err != nil {
-			s.logger.Error("Failed to post discussion", "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to post discussion"})
-			return
+		if result == nil {
+			continue
 		}
+		results = append(results, gin.H{
+			"project_id": target.ProjectID,
+			"mr_id":      target.MergeRequestIID,
+			"passed":     result.Passed,
+			"failures":   len(result.Failures),
+		})
+	}
 
-		// Set commit status
-		status := "success"
-		if !result.Passed {
-			status = "failed"
-		}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Processed successfully",
+		"results": results,
+	})
+}
 
-		if err := s.gitlabClient.SetCommitStatus(parsedEvent.Project.ID, parsedEvent.ObjectAttributes.LastCommit.ID, status, "MR Conformity Check"); err != nil {
-			s.logger.Error("Failed to set commit status", "error", err)
-		}
+// checkAndReport checks a merge request, posts the results and sets the commit status.
+// A nil result without error means the check itself failed and was logged.
+func (s *Server) checkAndReport(target recheckTarget) (*conformity.CheckResult, error) {
+	s.logger.Info("Checking merge request",
+		"project_id", target.ProjectID,
+		"mr_id", target.MergeRequestIID,
+		"inputs", target.Inputs)
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":  "Processed successfully",
-			"passed":   result.Passed,
-			"failures": len(result.Failures),
-		})
+	// Check merge request conformity
+	result, err := s.checkMergeRequest(target.ProjectID, target.MergeRequestIID, target.Inputs)
+	if err != nil {
+		s.logger.Error("Failed to check merge request",
+			"project_id", target.ProjectID,
+			"mr_id", target.MergeRequestIID,
+			"error", err)
+		return nil, nil
+	}
+
+	// Drafts checked silently keep their result for later rechecks only
+	if result.Silent {
+		s.logger.Info("Not reporting draft merge request", "project_id", target.ProjectID, "mr_id", target.MergeRequestIID)
+		return result, nil
+	}
+
+	// Post discussion with results
+	if err := s.gitlabClient.CreateUpdateMergeRequestDiscussion(target.ProjectID, target.MergeRequestIID, result.Summary, result.Passed); err != nil {
+		s.logger.Error("Failed to post discussion", "error", err)
+		return nil, fmt.Errorf("failed to post discussion")
+	}
+
+	// Set commit status
+	status := "success"
+	if !result.Passed {
+		status = "failed"
+	}
+
+	if err := s.gitlabClient.SetCommitStatus(target.ProjectID, result.SHA, status, "MR Conformity Check"); err != nil {
+		s.logger.Error("Failed to set commit status", "error", err)
+	}
+
+	return result, nil
+}
+
+// checkAndReportAll checks and reports merge requests one after the other, the failure of one
+// not stopping the others
+func (s *Server) checkAndReportAll(targets []recheckTarget) {
+	for _, target := range targets {
+		if _, err := s.checkAndReport(target); err != nil {
+			s.logger.Error("Failed to report merge request check",
+				"project_id", target.ProjectID,
+				"mr_id", target.MergeRequestIID,
+				"error", err)
+		}
 	}
 }
 
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gitlab-mr-conformity-bot/internal/conformity"
+	"gitlab-mr-conformity-bot/internal/conformity/rules"
+
+	gitlabapi "gitlab.com/gitlab-org/api/client-go"
+)
+
+// recheckTarget is a merge request to check again after a webhook event
+type recheckTarget struct {
+	ProjectID       int
+	MergeRequestIID int
+	Inputs          []rules.Input // Inputs changed by the event, empty to check every rule
+}
+
+// approvalActions are the merge request event actions only changing approvals
+var approvalActions = []string{"approved", "unapproved", "approval", "unapproval"}
+
+// closingActions are the merge request event actions ending the review of a merge request
+var closingActions = []string{"close", "merge"}
+
+// resultTTL is how long the last check result of a merge request is kept for rechecks
+const resultTTL = 7 * 24 * time.Hour
+
+// recheckTargets maps a webhook event to the open merge requests it affects
+func (s *Server) recheckTargets(event interface{}) ([]recheckTarget, error) {
+	switch event := event.(type) {
+	case *gitlabapi.MergeEvent:
+		// Closed and merged merge requests are no longer checked, forget their last result
+		if slices.Contains(closingActions, event.ObjectAttributes.Action) {
+			if err := s.storage.Delete(resultKey(event.Project.ID, event.ObjectAttributes.IID)); err != nil {
+				s.logger.Warn("Failed to delete check result", "projectId", event.Project.ID, "mrId", event.ObjectAttributes.IID, "error", err)
+			}
+			return nil, nil
+		}
+
+		var inputs []rules.Input
+		if isMarkedReady(event) {
+			// Drafts may have been skipped or partially checked, check every rule
+			s.logger.Info("Merge request marked as ready", "projectId", event.Project.ID, "mrId", event.ObjectAttributes.IID)
+		} else if slices.Contains(approvalActions, event.ObjectAttributes.Action) {
+			inputs = []rules.Input{rules.InputApprovals}
+		}
+		return []recheckTarget{{
+			ProjectID:       event.Project.ID,
+			MergeRequestIID: event.ObjectAttributes.IID,
+			Inputs:          inputs,
+		}}, nil
+
+	case *gitlabapi.PipelineEvent:
+		if !isRelevantPipelineEvent(event) {
+			return nil, nil
+		}
+		inputs := []rules.Input{rules.InputPipeline}
+
+		// Merge request pipelines reference their merge request
+		if event.MergeRequest.IID != 0 {
+			if event.MergeRequest.State != "opened" {
+				return nil, nil
+			}
+			return []recheckTarget{{
+				ProjectID:       event.MergeRequest.TargetProjectID,
+				MergeRequestIID: event.MergeRequest.IID,
+				Inputs:          inputs,
+			}}, nil
+		}
+
+		// Branch pipelines apply to the open merge requests whose head is the pipeline commit
+		if event.ObjectAttributes.Tag {
+			return nil, nil
+		}
+		mrs, err := s.gitlabClient.ListOpenMergeRequests(event.Project.ID, event.ObjectAttributes.Ref, "")
+		if err != nil {
+			return nil, err
+		}
+		var targets []recheckTarget
+		for _, mr := range mrs {
+			if mr.SHA == event.ObjectAttributes.SHA {
+				targets = append(targets, recheckTarget{ProjectID: mr.ProjectID, MergeRequestIID: mr.IID, Inputs: inputs})
+			}
+		}
+		return targets, nil
+
+	case *gitlabapi.PushEvent:
+		// Pushes to a source branch already raise merge request update events, but pushes to
+		// a target branch change the merge base and thereby the changes of its merge requests
+		branch, isBranch := strings.CutPrefix(event.Ref, "refs/heads/")
+		if !isBranch || event.CheckoutSHA == "" {
+			return nil, nil
+		}
+		mrs, err := s.gitlabClient.ListOpenMergeRequests(event.ProjectID, "", branch)
+		if err != nil {
+			return nil, err
+		}
+		var targets []recheckTarget
+		for _, mr := range mrs {
+			targets = append(targets, recheckTarget{ProjectID: mr.ProjectID, MergeRequestIID: mr.IID})
+		}
+		return targets, nil
+	}
+
+	return nil, nil
+}
+
+// checkMergeRequest checks a merge request, only re-evaluating the rules depending on the changed
+// inputs when a previous result is available, and stores the result for later rechecks. Results
+// are stored as JSON so replicas sharing the storage can read them.
+func (s *Server) checkMergeRequest(projectID interface{}, mrID int, changed []rules.Input) (*conformity.CheckResult, error) {
+	key := resultKey(projectID, mrID)
+	previous := s.storedResult(key)
+
+	result, err := s.checker.RecheckMergeRequest(projectID, mrID, changed, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result)
+	if err == nil {
+		err = s.storage.Set(key, data, resultTTL)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to store check result", "projectId", projectID, "mrId", mrID, "error", err)
+	}
+	return result, nil
+}
+
+// storedResult returns the last check result stored under a key, nil when none can be read
+func (s *Server) storedResult(key string) *conformity.CheckResult {
+	value, err := s.storage.Get(key)
+	if err != nil {
+		s.logger.Warn("Failed to read check result", "key", key, "error", err)
+		return nil
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	result := new(conformity.CheckResult)
+	if err := json.Unmarshal(data, result); err != nil {
+		s.logger.Warn("Failed to decode check result", "key", key, "error", err)
+		return nil
+	}
+	return result
+}
+
+// resultKey is the storage key of the last check result of a merge request
+func resultKey(projectID interface{}, mrID int) string {
+	return fmt.Sprintf("check-result:%v:%d", projectID, mrID)
+}
+
+// isMarkedReady reports whether a merge request event marks a draft as ready
+func isMarkedReady(event *gitlabapi.MergeEvent) bool {
+	return event.ObjectAttributes.Action == "update" && event.Changes.Draft.Previous && !event.Changes.Draft.Current
+}
+
+// isRelevantPipelineEvent reports whether a pipeline event may change the result of its merge requests.
+// External pipelines are ignored as they are created by commit statuses, including the bot's own.
+func isRelevantPipelineEvent(event *gitlabapi.PipelineEvent) bool {
+	if event.ObjectAttributes.Source == "external" {
+		return false
+	}
+	switch event.ObjectAttributes.Status {
+	case "success", "failed", "canceled", "skipped", "manual":
+		return true
+	}
+	return false
+}
+
+// inputNames converts rule inputs for storage in queued jobs
+func inputNames(inputs []rules.Input) []string {
+	names := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		names = append(names, string(input))
+	}
+	return names
+}
+
+// parseInputs converts the inputs stored in queued jobs
+func parseInputs(names []string) []rules.Input {
+	inputs := make([]rules.Input, 0, len(names))
+	for _, name := range names {
+		inputs = append(inputs, rules.Input(name))
+	}
+	return inputs
+}
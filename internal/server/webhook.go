@@ -18,7 +18,7 @@ import (
 func (s *Server) HandleWebhook(c *gin.Context) {
 	wh := Webhook{
 		Secret:         s.config.GitLab.SecretToken,
-		EventsToAccept: []gitlabapi.EventType{gitlabapi.EventTypeMergeRequest, gitlabapi.EventTypeNote},
+		EventsToAccept: []gitlabapi.EventType{gitlabapi.EventTypeMergeRequest, gitlabapi.EventTypeNote, gitlabapi.EventTypePipeline, gitlabapi.EventTypePush},
 	}
 
 	// If we have a secret set, we should check if the request matches it.
@@ -60,26 +60,45 @@ func (s *Server) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	switch parsedEvent := parsedEvent.(type) {
-	case *gitlabapi.MergeEvent:
+	if event, ok := parsedEvent.(*gitlabapi.MergeEvent); ok {
 		s.logger.Info("Processing merge request event",
-			"projectId", parsedEvent.Project.ID,
-			"mrId", parsedEvent.ObjectAttributes.IID,
-			"action", parsedEvent.ObjectAttributes.Action)
+			"projectId", event.Project.ID,
+			"mrId", event.ObjectAttributes.IID,
+			"action", event.ObjectAttributes.Action)
+	}
+
+	// Run the commands of merge request comments in turn with the other jobs of the merge request
+	if note, ok := commandNote(parsedEvent); ok {
+		jobID, err := s.queueManager.EnqueueCommand(c, strconv.Itoa(note.ProjectID), strconv.Itoa(note.MergeRequest.IID), note)
+		if err != nil {
+			s.logger.Error("Failed to enqueue commands", "projectId", note.ProjectID, "mrId", note.MergeRequest.IID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue commands"})
+			return
+		}
+		s.logger.Info("Commands enqueued successfully", "jobId", jobID, "projectId", note.ProjectID, "mrId", note.MergeRequest.IID)
+	}
+
+	// Map the event to the merge requests it affects
+	targets, err := s.recheckTargets(parsedEvent)
+	if err != nil {
+		s.logger.Error("Failed to find merge requests affected by event", "event", eventType, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find affected merge requests"})
+		return
+	}
 
-		pID := strconv.Itoa(parsedEvent.Project.ID)
-		mrID := strconv.Itoa(parsedEvent.ObjectAttributes.IID)
+	mergeEvent, _ := parsedEvent.(*gitlabapi.MergeEvent)
+	for _, target := range targets {
+		pID := strconv.Itoa(target.ProjectID)
+		mrID := strconv.Itoa(target.MergeRequestIID)
 		// Enqueue the webhook for processing
-		jobID, err := s.queueManager.EnqueueWebhook(c, pID, mrID, parsedEvent.EventType, parsedEvent)
+		jobID, err := s.queueManager.EnqueueWebhook(c, pID, mrID, string(eventType), mergeEvent, inputNames(target.Inputs))
 		if err != nil {
 			s.logger.Error("Failed to enqueue webhook event", "error", err)
 			return
 		}
 		//log.Printf("Webhook enqueued successfully with job ID: %s", jobID)
-		s.logger.Info("Webhook enqueued successfully", "jobId", jobID)
-		return
+		s.logger.Info("Webhook enqueued successfully", "jobId", jobID, "event", eventType, "inputs", target.Inputs)
 	}
-
 }
 
 // ProcessJob implements the JobProcessor interface
@@ -91,8 +110,14 @@ func (s *Server) ProcessJob(c context.Context, job *queue.WebhookJob) error {
 		fmt.Println("Error converting string to int:", err)
 	}
 
+	// Comments only run their commands
+	if job.Comment != nil {
+		s.runCommands(job.Comment)
+		return nil
+	}
+
 	// Check merge request conformity
-	result, err := s.checker.CheckMergeRequest(job.ProjectID, mrID)
+	result, err := s.checkMergeRequest(job.ProjectID, mrID, parseInputs(job.Inputs))
 	if err != nil {
 		s.logger.Error("Failed to check merge request",
 			"jobId", job.ID,
@@ -102,6 +127,12 @@ func (s *Server) ProcessJob(c context.Context, job *queue.WebhookJob) error {
 		return err
 	}
 
+	// Drafts checked silently keep their result for later rechecks only
+	if result.Silent {
+		s.logger.Info("Not reporting draft merge request", "jobId", job.ID, "projectId", job.ProjectID, "mrId", job.MergeRequestIID)
+		return nil
+	}
+
 	// Post discussion with results
 	if err := s.gitlabClient.CreateUpdateMergeRequestDiscussion(job.ProjectID, mrID, result.Summary, result.Passed); err != nil {
 		s.logger.Error("Failed to post discussion",
@@ -118,7 +149,7 @@ func (s *Server) ProcessJob(c context.Context, job *queue.WebhookJob) error {
 		status = "failed"
 	}
 
-	if err := s.gitlabClient.SetCommitStatus(job.ProjectID, job.Payload.ObjectAttributes.LastCommit.ID, status, "MR Conformity Check"); err != nil {
+	if err := s.gitlabClient.SetCommitStatus(job.ProjectID, result.SHA, status, "MR Conformity Check"); err != nil {
 		s.logger.Error("Failed to set commit status",
 			"jobId", job.ID,
 			"projectId", job.ProjectID,
@@ -2,34 +2,66 @@ package storage
 
 import (
 	"sync"
+	"time"
 )
 
+// sweepInterval is how often expired values are removed from memory
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	value   interface{}
+	expires time.Time // Zero when the value does not expire
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// MemoryStorage keeps values in the memory of the process
 type MemoryStorage struct {
-	data map[string]interface{}
-	mu   sync.RWMutex
+	data      map[string]memoryEntry
+	lastSweep time.Time
+	mu        sync.RWMutex
 }
 
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		data: make(map[string]interface{}),
+		data:      make(map[string]memoryEntry),
+		lastSweep: time.Now(),
 	}
 }
 
-func (m *MemoryStorage) Set(key string, value interface{}) error {
+func (m *MemoryStorage) Set(key string, value interface{}, ttl time.Duration) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.data[key] = value
+
+	now := time.Now()
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+	m.data[key] = entry
+
+	// Remove the expired values never read again
+	if now.Sub(m.lastSweep) > sweepInterval {
+		for k, e := range m.data {
+			if e.expired(now) {
+				delete(m.data, k)
+			}
+		}
+		m.lastSweep = now
+	}
 	return nil
 }
 
 func (m *MemoryStorage) Get(key string) (interface{}, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	value, exists := m.data[key]
-	if !exists {
+	entry, exists := m.data[key]
+	if !exists || entry.expired(time.Now()) {
 		return nil, nil
 	}
-	return value, nil
+	return entry.value, nil
 }
 
 func (m *MemoryStorage) Delete(key string) error {
@@ -42,6 +74,6 @@ func (m *MemoryStorage) Delete(key string) error {
 func (m *MemoryStorage) Exists(key string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	_, exists := m.data[key]
-	return exists
+	entry, exists := m.data[key]
+	return exists && !entry.expired(time.Now())
 }
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisTimeout bounds each Redis operation
+const redisTimeout = 5 * time.Second
+
+// RedisStorage keeps values in Redis, shared by every replica of the bot. Values are stored as
+// given, []byte or string, and read back as []byte.
+type RedisStorage struct {
+	redis  *redis.Client
+	prefix string
+}
+
+func NewRedisStorage(host, password string, db int, prefix string) *RedisStorage {
+	return &RedisStorage{
+		redis: redis.NewClient(&redis.Options{
+			Addr:     host,
+			Password: password,
+			DB:       db,
+		}),
+		prefix: prefix,
+	}
+}
+
+func (r *RedisStorage) Set(key string, value interface{}, ttl time.Duration) error {
+	c, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := r.redis.Set(c, r.key(key), value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+func (r *RedisStorage) Get(key string) (interface{}, error) {
+	c, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	value, err := r.redis.Get(c, r.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (r *RedisStorage) Delete(key string) error {
+	c, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return r.redis.Del(c, r.key(key)).Err()
+}
+
+func (r *RedisStorage) Exists(key string) bool {
+	c, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	count, err := r.redis.Exists(c, r.key(key)).Result()
+	return err == nil && count > 0
+}
+
+// Close closes the connection to Redis
+func (r *RedisStorage) Close() error {
+	return r.redis.Close()
+}
+
+func (r *RedisStorage) key(key string) string {
+	return fmt.Sprintf("%s:%s", r.prefix, key)
+}
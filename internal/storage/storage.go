@@ -1,7 +1,10 @@
 package storage
 
+import "time"
+
+// Storage keeps values by key. A zero TTL keeps a value until it is deleted.
 type Storage interface {
-	Set(key string, value interface{}) error
+	Set(key string, value interface{}, ttl time.Duration) error
 	Get(key string) (interface{}, error)
 	Delete(key string) error
 	Exists(key string) bool